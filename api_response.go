@@ -0,0 +1,157 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+)
+
+// 表示 go-webapi 的回执信封： {"Code":0, "Message":"", "Data":...} 。
+type ApiEnvelope struct {
+	Code    int    // 0 表示成功，非 0 表示错误。
+	Message string // Code 不为 0 时的错误描述。
+	Data    any    // 返回的主数据，为 JSON 解析后的值。
+}
+
+// 是否表示调用成功，即 Code 为 0 。
+func (x *ApiEnvelope) IsSuccess() bool {
+	return x.Code == 0
+}
+
+// 匹配 JSONP 格式的回执，如 callback({...}); 。
+var jsonpPattern = regexp.MustCompile(`(?s)^\s*[\w$.]+\s*\((.*)\)\s*;?\s*$`)
+
+// 尝试将 body 解析为 go-webapi 的回执信封，支持 JSON 和 JSONP 格式。
+// 仅当 body 是恰好包含 Code 、 Message 、 Data 三个字段的 JSON 对象，且 Code 是整数、 Message 是字符串时，
+// 才被视为信封，否则返回 ok=false 。
+func ParseApiEnvelope(body []byte) (env *ApiEnvelope, ok bool) {
+	if m := jsonpPattern.FindSubmatch(body); m != nil {
+		body = m[1]
+	}
+
+	var fields map[string]json.RawMessage
+	if json.Unmarshal(body, &fields) != nil || len(fields) != 3 {
+		return nil, false
+	}
+
+	rawCode, hasCode := fields["Code"]
+	rawMessage, hasMessage := fields["Message"]
+	rawData, hasData := fields["Data"]
+	if !hasCode || !hasMessage || !hasData {
+		return nil, false
+	}
+
+	env = new(ApiEnvelope)
+	if json.Unmarshal(rawCode, &env.Code) != nil {
+		return nil, false
+	}
+
+	if json.Unmarshal(rawMessage, &env.Message) != nil {
+		return nil, false
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(rawData))
+	dec.UseNumber() // 保留数值的原文，避免大整数丢失精度。
+	if dec.Decode(&env.Data) != nil {
+		return nil, false
+	}
+
+	return env, true
+}
+
+// 将 JSON 解析后的值展开为树形结构，用于 [widget.Tree] 。
+// 节点的 ID 是该节点在 JSON 中的路径，如 Data.List[0].Name ；根节点的 ID 为空字符串。
+type jsonTree struct {
+	children map[string][]string // 每个分支节点的子节点。
+	values   map[string]any      // 每个节点的值。
+	labels   map[string]string   // 每个节点在树上展示的文本。
+}
+
+// 以给定的名称作为唯一的顶层节点，展开 v 。
+func newJsonTree(rootName string, v any) *jsonTree {
+	t := &jsonTree{
+		children: map[string][]string{},
+		values:   map[string]any{},
+		labels:   map[string]string{},
+	}
+	t.children[""] = []string{rootName}
+	t.add(rootName, rootName, v)
+	return t
+}
+
+func (x *jsonTree) add(path, name string, v any) {
+	x.values[path] = v
+
+	switch vv := v.(type) {
+	case map[string]any:
+		keys := make([]string, 0, len(vv))
+		for k := range vv {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		children := make([]string, 0, len(keys))
+		for _, k := range keys {
+			childPath := jsonFieldPath(path, k)
+			children = append(children, childPath)
+			x.add(childPath, k, vv[k])
+		}
+		x.children[path] = children
+		x.labels[path] = fmt.Sprintf("%s {%d}", name, len(vv))
+
+	case []any:
+		children := make([]string, 0, len(vv))
+		for i, item := range vv {
+			childPath := path + "[" + strconv.Itoa(i) + "]"
+			children = append(children, childPath)
+			x.add(childPath, "["+strconv.Itoa(i)+"]", item)
+		}
+		x.children[path] = children
+		x.labels[path] = fmt.Sprintf("%s [%d]", name, len(vv))
+
+	default:
+		x.labels[path] = name + ": " + formatJsonValue(v, false)
+	}
+}
+
+func (x *jsonTree) isBranch(path string) bool {
+	_, ok := x.children[path]
+	return ok
+}
+
+// 返回节点的值的文本形式，用于复制：字符串返回原文，其余返回 JSON 。
+func (x *jsonTree) valueText(path string) string {
+	return formatJsonValue(x.values[path], true)
+}
+
+var identifierPattern = regexp.MustCompile(`^[A-Za-z_$][\w$]*$`)
+
+// 拼接对象字段的路径。字段名不是合法的标识符时，使用 ["name"] 的形式。
+func jsonFieldPath(parent, field string) string {
+	if identifierPattern.MatchString(field) {
+		return parent + "." + field
+	}
+	return parent + "[" + strconv.Quote(field) + "]"
+}
+
+// 格式化 JSON 的值。 rawString 为 true 时，字符串不加引号；对象和数组总是输出缩进的 JSON 。
+func formatJsonValue(v any, rawString bool) string {
+	if s, ok := v.(string); ok && rawString {
+		return s
+	}
+
+	buf := new(bytes.Buffer)
+	enc := json.NewEncoder(buf)
+	enc.SetEscapeHTML(false)
+
+	switch v.(type) {
+	case map[string]any, []any:
+		enc.SetIndent("", "    ")
+	}
+
+	enc.Encode(v)
+	return string(bytes.TrimSuffix(buf.Bytes(), []byte("\n")))
+}
@@ -0,0 +1,68 @@
+package client
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseApiEnvelope(t *testing.T) {
+	t.Run("json", func(t *testing.T) {
+		r := require.New(t)
+		env, ok := ParseApiEnvelope([]byte(`{"Code":0,"Message":"","Data":{"Id":12345678901234567890}}`))
+		r.True(ok)
+		r.True(env.IsSuccess())
+		r.Equal(map[string]any{"Id": json.Number("12345678901234567890")}, env.Data)
+	})
+
+	t.Run("jsonp", func(t *testing.T) {
+		r := require.New(t)
+		env, ok := ParseApiEnvelope([]byte(`cb({"Code":500,"Message":"oops","Data":null});`))
+		r.True(ok)
+		r.False(env.IsSuccess())
+		r.Equal(500, env.Code)
+		r.Equal("oops", env.Message)
+		r.Nil(env.Data)
+	})
+
+	t.Run("not-envelope", func(t *testing.T) {
+		r := require.New(t)
+		for _, body := range []string{
+			`plain text`,
+			`[1,2]`,
+			`{"Code":0,"Message":""}`,
+			`{"Code":0,"Message":"","Data":1,"Extra":1}`,
+			`{"Code":"0","Message":"","Data":1}`,
+			`{"Code":0,"Message":1,"Data":1}`,
+		} {
+			_, ok := ParseApiEnvelope([]byte(body))
+			r.False(ok, body)
+		}
+	})
+}
+
+func TestJsonTree(t *testing.T) {
+	r := require.New(t)
+
+	var v any
+	r.NoError(json.Unmarshal([]byte(`{"b":[1,{"x":"s"}],"a.b":true}`), &v))
+	tree := newJsonTree("Data", v)
+
+	r.Equal([]string{"Data"}, tree.children[""])
+	r.Equal([]string{`Data["a.b"]`, "Data.b"}, tree.children["Data"])
+	r.Equal([]string{"Data.b[0]", "Data.b[1]"}, tree.children["Data.b"])
+	r.Equal([]string{"Data.b[1].x"}, tree.children["Data.b[1]"])
+
+	r.True(tree.isBranch("Data.b"))
+	r.False(tree.isBranch("Data.b[0]"))
+
+	r.Equal("Data {2}", tree.labels["Data"])
+	r.Equal("b [2]", tree.labels["Data.b"])
+	r.Equal("[0]: 1", tree.labels["Data.b[0]"])
+	r.Equal(`x: "s"`, tree.labels["Data.b[1].x"])
+	r.Equal("a.b: true", tree.labels[`Data["a.b"]`])
+
+	r.Equal("s", tree.valueText("Data.b[1].x"))
+	r.Equal("{\n    \"x\": \"s\"\n}", tree.valueText("Data.b[1]"))
+}
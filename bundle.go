@@ -0,0 +1,365 @@
+package client
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strings"
+	"time"
+)
+
+/*
+配置包（ bundle ）：将多个 Client 的一组配置打包为一个文件，用于在不同的机器或成员间分享。
+
+支持两种格式：
+  - json 整个 [Bundle] 序列化为一个 JSON 文件。
+  - zip 包含 manifest.json （ [Bundle] 中除配置外的部分）和 configs/<ClientName>/<key>.json ，
+    与配置目录的结构一致，便于直接查看。
+
+[Bundle.Version] 用于兼容后续的格式变化，读取时拒绝高于 [BundleVersion] 的版本。
+*/
+
+// 当前的配置包格式的版本。
+const BundleVersion = 1
+
+// 配置包的文件格式。
+const (
+	BundleFormatJson = "json"
+	BundleFormatZip  = "zip"
+)
+
+// 导入配置包时，目标配置已存在的处理方式。
+const (
+	ConflictSkip      = "skip"      // 保留已有的配置，跳过导入的。
+	ConflictOverwrite = "overwrite" // 用导入的配置覆盖已有的，导入的配置中为空的敏感字段保留已有的值。
+	ConflictRename    = "rename"    // 将导入的配置保存为新的名称，如 key (1) 。
+)
+
+const bundleManifestName = "manifest.json"
+const bundleConfigDir = "configs"
+
+// 配置包。
+type Bundle struct {
+	Version int            // 格式的版本，见 [BundleVersion] 。
+	Created time.Time      // 创建的时间。
+	Configs []BundleConfig `json:",omitempty"`
+}
+
+// 配置包中的一个配置。
+type BundleConfig struct {
+	Client string         // 所属 Client 的名称，见 [Client.Name] 。
+	Key    string         // 配置的 key ，可以带有目录。
+	Config map[string]any // 配置的值，敏感字段为明文，或在导出时被清空。
+}
+
+// 导入配置包的结果，各项的值为 ClientName/key 。
+type BundleImportResult struct {
+	Imported []string          // 新导入或覆盖的配置。
+	Skipped  []string          // 因已存在而跳过的配置。
+	Renamed  map[string]string // 因已存在而改名保存的配置，值为新的 ClientName/key 。
+}
+
+// 表示一个配置。
+type ConfigRef struct {
+	Client string
+	Key    string
+}
+
+func (x ConfigRef) String() string {
+	return x.Client + "/" + x.Key
+}
+
+// 解析 ClientName/key 形式的字符串， key 中可以带有目录。
+func ParseConfigRef(s string) (ConfigRef, error) {
+	clientName, key, ok := strings.Cut(s, "/")
+	if !ok || clientName == "" || key == "" {
+		return ConfigRef{}, fmt.Errorf("%w: %q is not in the form of ClientName/key", ErrInvalidName, s)
+	}
+	return ConfigRef{Client: clientName, Key: key}, nil
+}
+
+// 将给定的配置打包。 stripSecrets 为 true 时，敏感字段（见 [ConfigManager.SetSecretKeys] ）被清空，
+// 否则以明文保存在配置包中。
+func (x *ConfigManager) ExportBundle(refs []ConfigRef, stripSecrets bool) (*Bundle, error) {
+	bundle := &Bundle{Version: BundleVersion, Created: time.Now()}
+
+	for _, ref := range refs {
+		conf, err := x.Load(ref.Client, ref.Key)
+		if err != nil {
+			return nil, err
+		}
+
+		if stripSecrets {
+			conf = x.stripSecrets(ref.Client, conf)
+		}
+
+		bundle.Configs = append(bundle.Configs, BundleConfig{Client: ref.Client, Key: ref.Key, Config: conf})
+	}
+
+	return bundle, nil
+}
+
+// 返回 conf 的副本，其中的敏感字段被清空。
+func (x *ConfigManager) stripSecrets(clientName string, conf map[string]any) map[string]any {
+	x.secrets.mu.RLock()
+	keys := x.secrets.keys[clientName]
+	x.secrets.mu.RUnlock()
+
+	if conf == nil || len(keys) == 0 {
+		return conf
+	}
+
+	res := make(map[string]any, len(conf))
+	for k, v := range conf {
+		res[k] = v
+	}
+
+	for _, k := range keys {
+		if _, ok := res[k].(string); ok {
+			res[k] = ""
+		}
+	}
+	return res
+}
+
+// 导入配置包中的配置。 conflict 为 ConflictXxx 之一，决定目标配置已存在时的处理方式。
+// 导出时敏感字段通常已被清空，覆盖已有的配置时，这些字段保留本地的值，见 [ConflictOverwrite] 。
+// 出错时停止导入，已导入的配置不会回滚，返回的结果中包含出错前的部分。
+func (x *ConfigManager) ImportBundle(bundle *Bundle, conflict string) (*BundleImportResult, error) {
+	switch conflict {
+	case ConflictSkip, ConflictOverwrite, ConflictRename:
+	default:
+		return nil, fmt.Errorf("unknown conflict resolution %q", conflict)
+	}
+
+	res := &BundleImportResult{Renamed: make(map[string]string)}
+	for _, item := range bundle.Configs {
+		ref := ConfigRef{Client: item.Client, Key: item.Key}
+		exists, err := x.Exists(item.Client, item.Key)
+		if err != nil {
+			return res, err
+		}
+
+		target := ref
+		conf := item.Config
+		if exists {
+			switch conflict {
+			case ConflictSkip:
+				res.Skipped = append(res.Skipped, ref.String())
+				continue
+
+			case ConflictOverwrite:
+				conf, err = x.keepSecrets(item.Client, item.Key, conf)
+				if err != nil {
+					return res, err
+				}
+
+			case ConflictRename:
+				target.Key, err = x.freeKey(item.Client, item.Key)
+				if err != nil {
+					return res, err
+				}
+			}
+		}
+
+		if err := x.Save(target.Client, target.Key, conf); err != nil {
+			return res, err
+		}
+
+		if target != ref {
+			res.Renamed[ref.String()] = target.String()
+		} else {
+			res.Imported = append(res.Imported, ref.String())
+		}
+	}
+
+	return res, nil
+}
+
+// 返回 conf 的副本，其中为空的敏感字段使用已保存的配置 key 中的值，避免被清空了敏感字段的配置包覆盖本地的凭据。
+// 已保存的配置无法解析时，没有可保留的值，原样返回 conf 。
+func (x *ConfigManager) keepSecrets(clientName, key string, conf map[string]any) (map[string]any, error) {
+	x.secrets.mu.RLock()
+	keys := x.secrets.keys[clientName]
+	x.secrets.mu.RUnlock()
+
+	if len(keys) == 0 {
+		return conf, nil
+	}
+
+	old, err := x.Load(clientName, key)
+	switch {
+	case errors.Is(err, ErrCorruptConfig):
+		return conf, nil
+	case err != nil:
+		return nil, err
+	}
+
+	res := make(map[string]any, len(conf))
+	for k, v := range conf {
+		res[k] = v
+	}
+
+	for _, k := range keys {
+		if v, ok := res[k].(string); ok && v != "" {
+			continue
+		}
+
+		if v, ok := old[k].(string); ok && v != "" {
+			res[k] = v
+		}
+	}
+	return res, nil
+}
+
+// 返回一个不存在的 key ，形如 key (1) 、 key (2) 。
+func (x *ConfigManager) freeKey(clientName, key string) (string, error) {
+	for i := 1; ; i++ {
+		candidate := fmt.Sprintf("%s (%d)", key, i)
+		exists, err := x.Exists(clientName, candidate)
+		if err != nil {
+			return "", err
+		}
+
+		if !exists {
+			return candidate, nil
+		}
+	}
+}
+
+// 将配置包以给定的格式写入 w ， format 为 BundleFormatXxx 之一。
+func WriteBundle(w io.Writer, format string, bundle *Bundle) error {
+	switch format {
+	case BundleFormatJson:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(bundle)
+
+	case BundleFormatZip:
+		return writeBundleZip(w, bundle)
+
+	default:
+		return fmt.Errorf("unsupported bundle format %q", format)
+	}
+}
+
+func writeBundleZip(w io.Writer, bundle *Bundle) error {
+	zw := zip.NewWriter(w)
+
+	writeJson := func(name string, v any) error {
+		f, err := zw.Create(name)
+		if err != nil {
+			return err
+		}
+		enc := json.NewEncoder(f)
+		enc.SetIndent("", "  ")
+		return enc.Encode(v)
+	}
+
+	manifest := *bundle
+	manifest.Configs = nil
+	if err := writeJson(bundleManifestName, manifest); err != nil {
+		return err
+	}
+
+	for _, item := range bundle.Configs {
+		name := path.Join(bundleConfigDir, item.Client, item.Key+".json")
+		if err := writeJson(name, item.Config); err != nil {
+			return err
+		}
+	}
+
+	return zw.Close()
+}
+
+// 读取配置包，自动识别格式。配置包无效或版本不受支持时返回 [ErrCorruptConfig] 类别的错误。
+func ReadBundle(content []byte) (*Bundle, error) {
+	var bundle *Bundle
+	var err error
+	if bytes.HasPrefix(content, []byte("PK\x03\x04")) {
+		bundle, err = readBundleZip(content)
+	} else {
+		bundle = new(Bundle)
+		err = json.Unmarshal(content, bundle)
+	}
+
+	if err != nil {
+		return nil, wrapCorrupt(err)
+	}
+
+	switch {
+	case bundle.Version <= 0:
+		return nil, wrapCorrupt(errors.New("not a config bundle, the version is missing"))
+	case bundle.Version > BundleVersion:
+		return nil, wrapCorrupt(fmt.Errorf("unsupported bundle version %d, please upgrade the application", bundle.Version))
+	}
+
+	for _, item := range bundle.Configs {
+		if item.Client == "" || item.Key == "" {
+			return nil, wrapCorrupt(errors.New("each config in the bundle must have a Client and a Key"))
+		}
+	}
+
+	return bundle, nil
+}
+
+func readBundleZip(content []byte) (*Bundle, error) {
+	zr, err := zip.NewReader(bytes.NewReader(content), int64(len(content)))
+	if err != nil {
+		return nil, err
+	}
+
+	readJson := func(f *zip.File, v any) error {
+		r, err := f.Open()
+		if err != nil {
+			return err
+		}
+		defer r.Close()
+		return json.NewDecoder(r).Decode(v)
+	}
+
+	bundle := new(Bundle)
+	hasManifest := false
+	for _, f := range zr.File {
+		if f.Name == bundleManifestName {
+			if err := readJson(f, bundle); err != nil {
+				return nil, fmt.Errorf("%s: %w", f.Name, err)
+			}
+			hasManifest = true
+			break
+		}
+	}
+
+	if !hasManifest {
+		return nil, fmt.Errorf("%s is missing", bundleManifestName)
+	}
+
+	bundle.Configs = nil
+	for _, f := range zr.File {
+		rel := strings.TrimPrefix(f.Name, bundleConfigDir+"/")
+		if rel == f.Name || !strings.HasSuffix(rel, ".json") || strings.HasSuffix(f.Name, "/") {
+			continue
+		}
+
+		clientName, key, _ := strings.Cut(strings.TrimSuffix(rel, ".json"), "/")
+		item := BundleConfig{Client: clientName, Key: key}
+		if err := readJson(f, &item.Config); err != nil {
+			return nil, fmt.Errorf("%s: %w", f.Name, err)
+		}
+		bundle.Configs = append(bundle.Configs, item)
+	}
+
+	sort.Slice(bundle.Configs, func(i, j int) bool {
+		a, b := bundle.Configs[i], bundle.Configs[j]
+		if a.Client != b.Client {
+			return a.Client < b.Client
+		}
+		return a.Key < b.Key
+	})
+	return bundle, nil
+}
@@ -0,0 +1,132 @@
+package client
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseConfigRef(t *testing.T) {
+	r := require.New(t)
+
+	ref, err := ParseConfigRef("C/f/k")
+	r.NoError(err)
+	r.Equal(ConfigRef{Client: "C", Key: "f/k"}, ref)
+	r.Equal("C/f/k", ref.String())
+
+	for _, v := range []string{"", "C", "C/", "/k"} {
+		_, err = ParseConfigRef(v)
+		r.ErrorIs(err, ErrInvalidName, v)
+	}
+}
+
+func TestBundle(t *testing.T) {
+	clearAllConfig()
+	m := NewConfigManager(_CONFIG_PATH)
+	m.SetSecretKeys("A", []string{"Secret"})
+	r := require.New(t)
+
+	r.NoError(m.Save("A", "a1", map[string]any{"Uri": "u1", "Secret": "s1"}))
+	r.NoError(m.Save("A", "f/a2", map[string]any{"Uri": "u2"}))
+	r.NoError(m.Save("B", "b1", map[string]any{"Uri": "u3"}))
+
+	refs := []ConfigRef{{"A", "a1"}, {"A", "f/a2"}, {"B", "b1"}}
+
+	bundle, err := m.ExportBundle(refs, false)
+	r.NoError(err)
+	r.Equal(BundleVersion, bundle.Version)
+	r.Equal("s1", bundle.Configs[0].Config["Secret"])
+
+	bundle, err = m.ExportBundle(refs, true)
+	r.NoError(err)
+	r.Equal(map[string]any{"Uri": "u1", "Secret": ""}, bundle.Configs[0].Config)
+
+	_, err = m.ExportBundle([]ConfigRef{{"A", "not-exist"}}, false)
+	r.ErrorIs(err, ErrConfigNotFound)
+
+	for _, format := range []string{BundleFormatJson, BundleFormatZip} {
+		buf := new(bytes.Buffer)
+		r.NoError(WriteBundle(buf, format, bundle), format)
+
+		read, err := ReadBundle(buf.Bytes())
+		r.NoError(err, format)
+		r.Equal(bundle.Version, read.Version, format)
+		r.True(bundle.Created.Equal(read.Created), format)
+		r.Equal(bundle.Configs, read.Configs, format)
+	}
+
+	r.Error(WriteBundle(new(bytes.Buffer), "xml", bundle))
+
+	t.Run("import", func(t *testing.T) {
+		r := require.New(t)
+		r.NoError(m.Save("A", "a1", map[string]any{"Uri": "changed"}))
+		r.NoError(m.Remove("B", "b1"))
+
+		res, err := m.ImportBundle(bundle, ConflictSkip)
+		r.NoError(err)
+		r.Equal([]string{"B/b1"}, res.Imported)
+		r.Equal([]string{"A/a1", "A/f/a2"}, res.Skipped)
+		r.Empty(res.Renamed)
+
+		conf, err := m.Load("A", "a1")
+		r.NoError(err)
+		r.Equal("changed", conf["Uri"])
+
+		res, err = m.ImportBundle(bundle, ConflictRename)
+		r.NoError(err)
+		r.Empty(res.Imported)
+		r.Equal(map[string]string{"A/a1": "A/a1 (1)", "A/f/a2": "A/f/a2 (1)", "B/b1": "B/b1 (1)"}, res.Renamed)
+
+		res, err = m.ImportBundle(bundle, ConflictRename)
+		r.NoError(err)
+		r.Equal("A/a1 (2)", res.Renamed["A/a1"])
+
+		// 覆盖时，配置包中被清空的敏感字段保留本地的值。
+		r.NoError(m.Save("A", "a1", map[string]any{"Uri": "changed", "Secret": "local"}))
+		res, err = m.ImportBundle(bundle, ConflictOverwrite)
+		r.NoError(err)
+		r.Equal([]string{"A/a1", "A/f/a2", "B/b1"}, res.Imported)
+
+		conf, err = m.Load("A", "a1")
+		r.NoError(err)
+		r.Equal(map[string]any{"Uri": "u1", "Secret": "local"}, conf)
+
+		// 配置包中带有敏感字段时，覆盖本地的值。
+		withSecrets := &Bundle{Version: 1, Configs: []BundleConfig{{Client: "A", Key: "a1", Config: map[string]any{"Uri": "u1", "Secret": "s2"}}}}
+		_, err = m.ImportBundle(withSecrets, ConflictOverwrite)
+		r.NoError(err)
+		conf, err = m.Load("A", "a1")
+		r.NoError(err)
+		r.Equal("s2", conf["Secret"])
+
+		_, err = m.ImportBundle(bundle, "bad")
+		r.Error(err)
+
+		// 配置包中的名称同样需要校验。
+		_, err = m.ImportBundle(&Bundle{Version: 1, Configs: []BundleConfig{{Client: "A", Key: "../x"}}}, ConflictSkip)
+		r.ErrorIs(err, ErrInvalidName)
+	})
+
+	clearAllConfig()
+}
+
+func TestReadBundle_Errors(t *testing.T) {
+	r := require.New(t)
+
+	_, err := ReadBundle([]byte("{bad"))
+	r.ErrorIs(err, ErrCorruptConfig)
+
+	_, err = ReadBundle([]byte(`{"Key":"v"}`))
+	r.ErrorIs(err, ErrCorruptConfig)
+
+	_, err = ReadBundle([]byte(`{"Version":2}`))
+	r.ErrorIs(err, ErrCorruptConfig)
+	r.Contains(err.Error(), "unsupported bundle version 2")
+
+	_, err = ReadBundle([]byte(`{"Version":1,"Configs":[{"Client":"A"}]}`))
+	r.ErrorIs(err, ErrCorruptConfig)
+
+	_, err = ReadBundle([]byte("PK\x03\x04bad"))
+	r.ErrorIs(err, ErrCorruptConfig)
+}
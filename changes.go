@@ -0,0 +1,111 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"sync"
+
+	"fyne.io/fyne/v2/data/binding"
+)
+
+// 可选接口。 [Client] 实现此接口后，界面上的配置被修改时即时检查是否有未保存的改动；
+// 未实现时定时检查，见 main_window_changes.go 。
+type ChangeNotifier interface {
+	// 添加在界面上的配置被修改后调用的回调。回调可能在非 UI 线程上被调用。
+	AddChangeListener(fn func())
+}
+
+// 在 items 中任一项的值变化时调用 fn ，用于实现 [ChangeNotifier] 。
+func AddBindingListener(fn func(), items ...binding.DataItem) {
+	listener := binding.NewDataListener(fn)
+	for _, v := range items {
+		v.AddListener(listener)
+	}
+}
+
+// 跟踪 [Client] 界面上的配置相对于最近一次载入或保存时是否有改动。
+// 通过比较 [Client.GetConfig] 序列化后的 JSON 判断，因此适用于任何 [Client] 。
+// 可在多个 goroutine 中使用。
+type changeTracker struct {
+	mu     sync.Mutex
+	client Client // 当前跟踪的 Client ，为 nil 时不跟踪。
+	key    string // 当前载入的配置的 key ，未载入或未保存过时为空字符串。
+	saved  []byte // 载入或保存时的配置。
+	dirty  bool   // 最近一次 check 的结果。
+}
+
+// 以 c 当前的配置为基准，重新开始跟踪。 key 为当前载入的配置， c 的配置尚未保存过时为空字符串。
+func (x *changeTracker) reset(c Client, key string) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	x.client = c
+	x.key = key
+	x.saved = x.snapshot(c)
+	x.dirty = false
+}
+
+// 修改当前载入的配置的 key ，如配置被重命名或删除时，不影响改动的状态。
+func (x *changeTracker) setKey(key string) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	x.key = key
+}
+
+// 返回当前载入的配置的 key 。
+func (x *changeTracker) loadedKey() string {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	return x.key
+}
+
+// 重新比较配置，返回是否有改动，以及是否与上一次 check 的结果不同。
+func (x *changeTracker) check() (dirty, changed bool) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	dirty = x.client != nil && !bytes.Equal(x.saved, x.snapshot(x.client))
+	changed = dirty != x.dirty
+	x.dirty = dirty
+	return dirty, changed
+}
+
+// 返回 conf 是否与最近一次载入或保存时的配置相同，用于判断磁盘上的配置是否被外部修改。
+func (x *changeTracker) isSaved(conf map[string]any) bool {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	return bytes.Equal(x.saved, canonicalJSON(conf))
+}
+
+// 返回 key 对应的配置是否有未保存的改动，使用最近一次 check 的结果。
+func (x *changeTracker) isDirty(key string) bool {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	return x.dirty && x.key == key
+}
+
+func (x *changeTracker) snapshot(c Client) []byte {
+	if c == nil {
+		return nil
+	}
+
+	return canonicalJSON(c.GetConfig())
+}
+
+// 将 v 序列化为 JSON ，其中的结构体等先转换为 map ，使内容相同的配置总是得到相同的结果，
+// 无论来自 [Client.GetConfig] 还是从文件读取。
+func canonicalJSON(v any) []byte {
+	content, err := json.Marshal(v)
+	if err != nil {
+		return nil
+	}
+
+	var m any
+	if json.Unmarshal(content, &m) != nil {
+		return content
+	}
+
+	// map 的 key 在序列化时是排序的。
+	content, _ = json.Marshal(m)
+	return content
+}
@@ -0,0 +1,51 @@
+package client
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestChangeTracker(t *testing.T) {
+	r := require.New(t)
+	tracker := new(changeTracker)
+
+	dirty, changed := tracker.check()
+	r.False(dirty)
+	r.False(changed)
+
+	c := &fakeClient{config: map[string]any{"Result": "a", "Nested": map[string]any{"x": 1}}}
+	tracker.reset(c, "k")
+	r.Equal("k", tracker.loadedKey())
+
+	dirty, changed = tracker.check()
+	r.False(dirty)
+	r.False(changed)
+
+	c.SetConfig(map[string]any{"Result": "b", "Nested": map[string]any{"x": 1}})
+	dirty, changed = tracker.check()
+	r.True(dirty)
+	r.True(changed)
+	r.True(tracker.isDirty("k"))
+	r.False(tracker.isDirty("other"))
+	r.True(tracker.isSaved(map[string]any{"Result": "a", "Nested": map[string]any{"x": 1.0}}))
+	r.False(tracker.isSaved(c.GetConfig()))
+
+	dirty, changed = tracker.check()
+	r.True(dirty)
+	r.False(changed)
+
+	// 改回原值后不再视为有改动。
+	c.SetConfig(map[string]any{"Nested": map[string]any{"x": 1}, "Result": "a"})
+	dirty, changed = tracker.check()
+	r.False(dirty)
+	r.True(changed)
+	r.False(tracker.isDirty("k"))
+
+	// 重新开始跟踪。
+	c.SetConfig(map[string]any{"Result": "c"})
+	tracker.reset(c, "")
+	dirty, _ = tracker.check()
+	r.False(dirty)
+	r.Equal("", tracker.loadedKey())
+}
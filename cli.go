@@ -0,0 +1,572 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+)
+
+// 可选接口。 [Client] 实现此接口后，可以在命令行模式下（无图形界面）执行请求。
+type Executor interface {
+	// 使用当前的配置（即 [Client.SetConfig] 设置的值）执行请求，返回格式化后的响应内容。
+	// 请求未成功（如响应的状态码不是 2xx ）时返回 error ，使命令行以非 0 的退出码结束。
+	Execute(ctx context.Context) (string, error)
+}
+
+// 命令行模式的退出码。
+const (
+	ExitOK    = 0 // 执行成功。
+	ExitError = 1 // 执行过程出错，如请求失败、配置不存在等。
+	ExitUsage = 2 // 命令行参数有误。
+)
+
+// 命令行模式的运行参数。
+type CommandOption struct {
+	ConfigPath string    // 指定存储配置的目录。若为空，则使用 [GetDefaultConfigDir] 。
+	Clients    []Client  // 可用的 [Client] 。
+	Stdout     io.Writer // 输出执行结果。
+	Stderr     io.Writer // 输出错误信息和帮助。
+}
+
+// 以命令行模式执行 args 给定的子命令，返回进程的退出码。
+//
+// 支持的子命令：
+//   - list [-client NAME] 未给定 -client 时，列出所有 [Client] 的名称；否则列出该 [Client] 下的所有配置。
+//   - call -client NAME -config KEY [-env ENV] 读取已保存的配置并执行请求，将响应输出到 Stdout 。
+//     给定 -env 时，使用该环境中的变量替换配置中的 {{name}} 。
+//   - export -client NAME -config KEY [-env ENV] [-format FORMAT] [-unsigned] [-reveal-secrets] 将已保存的配置导出为代码片段，
+//     FORMAT 为 [ExportFormats] 之一，默认为 curl 。需要 [Client] 实现 [Exporter] 。
+//     敏感字段（见 [SecretMarker] ）默认被替换为 [RedactedText] ，给定 -reveal-secrets 时输出原值。
+//   - secrets status|enable -mode MODE|migrate 管理敏感字段的加密存储，见 [ConfigManager.EnableSecrets] 。
+//   - bundle export [-o FILE] [-format FORMAT] [-include-secrets] [NAME[/KEY] ...] 将配置打包，见 [Bundle] 。
+//     未给定 NAME 时打包所有 [Client] 的配置；只给定 NAME 时打包该 [Client] 的所有配置。未给定 -o 时输出到 Stdout 。
+//     敏感字段（见 [SecretMarker] ）默认被清空，给定 -include-secrets 时保留原值。
+//   - bundle import [-conflict skip|overwrite|rename] FILE 导入配置包，见 [ConfigManager.ImportBundle] 。
+//
+// 启用加密存储时，各子命令自动解锁； [SecretModePassphrase] 的口令从环境变量 [PassphraseEnvName] 读取。
+func RunCommand(ctx context.Context, op *CommandOption, args []string) int {
+	configPath := op.ConfigPath
+	if configPath == "" {
+		configPath = GetDefaultConfigDir()
+	}
+
+	cmd := &command{
+		option:        op,
+		configManager: NewConfigManager(configPath),
+	}
+
+	for _, c := range op.Clients {
+		if marker, ok := c.(SecretMarker); ok {
+			cmd.configManager.SetSecretKeys(c.Name(), marker.SecretKeys())
+		}
+	}
+
+	if len(args) == 0 {
+		cmd.usage()
+		return ExitUsage
+	}
+
+	var err error
+	switch args[0] {
+	case "list":
+		err = cmd.list(args[1:])
+	case "call":
+		err = cmd.call(ctx, args[1:])
+	case "export":
+		err = cmd.export(args[1:])
+	case "secrets":
+		err = cmd.secrets(args[1:])
+	case "bundle":
+		err = cmd.bundle(args[1:])
+	case "help", "-h", "-help", "--help":
+		cmd.usage()
+		return ExitOK
+	default:
+		fmt.Fprintf(op.Stderr, "unknown command %q\n", args[0])
+		cmd.usage()
+		return ExitUsage
+	}
+
+	if err == nil {
+		return ExitOK
+	}
+
+	if errors.Is(err, flag.ErrHelp) {
+		return ExitOK
+	}
+
+	var usageErr usageError
+	if errors.As(err, &usageErr) {
+		// 解析参数出错时 flag 包已经输出了错误信息，此时 usageErr 为空。
+		if usageErr != "" {
+			fmt.Fprintln(op.Stderr, err)
+		}
+		return ExitUsage
+	}
+
+	fmt.Fprintln(op.Stderr, err)
+	if errors.Is(err, ErrSecretsLocked) {
+		fmt.Fprintf(op.Stderr, "hint: in the passphrase mode, the passphrase is read from the environment variable %s\n", PassphraseEnvName)
+	}
+	return ExitError
+}
+
+// 表示命令行参数有误。
+type usageError string
+
+func (e usageError) Error() string {
+	return string(e)
+}
+
+type command struct {
+	option        *CommandOption
+	configManager *ConfigManager
+}
+
+func (x *command) usage() {
+	fmt.Fprint(x.option.Stderr, `Usage:
+  webapi-client [-c CONFIG_DIR]                                  start the GUI
+  webapi-client [-c CONFIG_DIR] list [-client NAME]              list clients, or the configs of a client
+  webapi-client [-c CONFIG_DIR] call -client NAME -config KEY [-env ENV]
+                                                                 perform the request of a saved config
+  webapi-client [-c CONFIG_DIR] export -client NAME -config KEY [-env ENV] [-format curl|go|httpie] [-unsigned] [-reveal-secrets]
+                                                                 print the request of a saved config as a snippet
+  webapi-client [-c CONFIG_DIR] secrets status                   show the state of the encrypted secret store
+  webapi-client [-c CONFIG_DIR] secrets enable -mode passphrase|keyring|file
+                                                                 encrypt secrets in configs from now on
+  webapi-client [-c CONFIG_DIR] secrets migrate                  encrypt secrets in existing plaintext configs
+  webapi-client [-c CONFIG_DIR] bundle export [-o FILE] [-format json|zip] [-include-secrets] [NAME[/KEY] ...]
+                                                                 pack configs of all or the given clients into a bundle
+  webapi-client [-c CONFIG_DIR] bundle import [-conflict skip|overwrite|rename] FILE
+                                                                 import configs from a bundle
+
+The passphrase of the secret store is read from the environment variable `+PassphraseEnvName+`.
+`)
+}
+
+func (x *command) newFlagSet(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(x.option.Stderr)
+	return fs
+}
+
+// 解析子命令的参数。出错时， -h 返回 [flag.ErrHelp] ，其他情况返回空的 [usageError] 。
+func (x *command) parseFlags(fs *flag.FlagSet, args []string) error {
+	err := fs.Parse(args)
+	if err == nil || err == flag.ErrHelp {
+		return err
+	}
+	return usageError("")
+}
+
+func (x *command) list(args []string) error {
+	fs := x.newFlagSet("list")
+	clientName := fs.String("client", "", "the name of the client")
+	if err := x.parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	if *clientName == "" {
+		for _, c := range x.option.Clients {
+			fmt.Fprintln(x.option.Stdout, c.Name())
+		}
+		return nil
+	}
+
+	c, err := x.findClient(*clientName)
+	if err != nil {
+		return err
+	}
+
+	keys, err := x.configManager.ListKeys(c.Name())
+	if err != nil {
+		return err
+	}
+
+	for _, key := range keys {
+		fmt.Fprintln(x.option.Stdout, key)
+	}
+	return nil
+}
+
+func (x *command) call(ctx context.Context, args []string) error {
+	fs := x.newFlagSet("call")
+	clientName := fs.String("client", "", "the name of the client")
+	configKey := fs.String("config", "", "the key of the saved config")
+	envName := fs.String("env", "", "the name of the environment whose variables are applied to the config")
+	if err := x.parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	c, err := x.loadClient(*clientName, *configKey, *envName)
+	if err != nil {
+		return err
+	}
+
+	executor, ok := c.(Executor)
+	if !ok {
+		return fmt.Errorf("client %q does not support the command line mode", c.Name())
+	}
+
+	res, err := executor.Execute(ctx)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintln(x.option.Stdout, res)
+	return nil
+}
+
+func (x *command) export(args []string) error {
+	fs := x.newFlagSet("export")
+	clientName := fs.String("client", "", "the name of the client")
+	configKey := fs.String("config", "", "the key of the saved config")
+	envName := fs.String("env", "", "the name of the environment whose variables are applied to the config")
+	format := fs.String("format", ExportFormatCurl, "the output format: "+strings.Join(ExportFormats, ", "))
+	unsigned := fs.Bool("unsigned", false, "do not sign the request, the Go snippet signs it before sending instead")
+	reveal := fs.Bool("reveal-secrets", false, "output sensitive fields as is instead of "+RedactedText)
+	if err := x.parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	if !isExportFormat(*format) {
+		return usageError(fmt.Sprintf("unsupported format %q", *format))
+	}
+
+	c, err := x.loadClient(*clientName, *configKey, *envName)
+	if err != nil {
+		return err
+	}
+
+	exporter, ok := c.(Exporter)
+	if !ok {
+		return fmt.Errorf("client %q does not support exporting", c.Name())
+	}
+
+	req, err := exporter.ExportRequest(!*unsigned)
+	if err != nil {
+		return err
+	}
+
+	snippet, err := ExportSnippet(*format, req)
+	if err != nil {
+		return err
+	}
+
+	if marker, ok := c.(SecretMarker); ok && !*reveal {
+		vars, err := x.loadVariables(c.Name(), *envName)
+		if err != nil {
+			return err
+		}
+		snippet = Redact(snippet, SensitiveValues(c.GetConfig(), marker.SecretKeys(), vars))
+	}
+
+	fmt.Fprintln(x.option.Stdout, snippet)
+	return nil
+}
+
+func (x *command) secrets(args []string) error {
+	if len(args) == 0 {
+		return usageError("secrets requires one of the commands: status, enable, migrate")
+	}
+
+	passphrase := os.Getenv(PassphraseEnvName)
+	switch args[0] {
+	case "status":
+		mode, err := x.configManager.SecretMode()
+		if err != nil {
+			return err
+		}
+
+		if mode == SecretModeNone {
+			fmt.Fprintln(x.option.Stdout, "disabled")
+			return nil
+		}
+
+		state := "unlocked"
+		if err := x.configManager.UnlockSecrets(passphrase); err != nil {
+			state = "locked: " + err.Error()
+		}
+		fmt.Fprintf(x.option.Stdout, "%s, %s\n", mode, state)
+		return nil
+
+	case "enable":
+		fs := x.newFlagSet("secrets enable")
+		mode := fs.String("mode", SecretModeKeyring, "where the master key comes from: passphrase, keyring or file")
+		if err := x.parseFlags(fs, args[1:]); err != nil {
+			return err
+		}
+
+		if *mode == SecretModePassphrase && passphrase == "" {
+			return usageError("the passphrase must be given by the environment variable " + PassphraseEnvName)
+		}
+
+		actual, err := x.configManager.EnableSecrets(*mode, passphrase)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(x.option.Stdout, actual)
+		return nil
+
+	case "migrate":
+		if err := x.unlockSecrets(); err != nil {
+			return err
+		}
+
+		for _, c := range x.option.Clients {
+			n, err := x.configManager.MigrateSecrets(c.Name())
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(x.option.Stdout, "%s: %d config(s) encrypted\n", c.Name(), n)
+		}
+		return nil
+
+	default:
+		return usageError(fmt.Sprintf("unknown secrets command %q", args[0]))
+	}
+}
+
+func (x *command) bundle(args []string) error {
+	if len(args) == 0 {
+		return usageError("bundle requires one of the commands: export, import")
+	}
+
+	switch args[0] {
+	case "export":
+		fs := x.newFlagSet("bundle export")
+		output := fs.String("o", "", "the output file, print to stdout if omitted")
+		format := fs.String("format", "", "the bundle format: json or zip, inferred from the output file name if omitted")
+		include := fs.Bool("include-secrets", false, "keep sensitive fields, such as secret keys, which are cleared by default")
+		if err := x.parseFlags(fs, args[1:]); err != nil {
+			return err
+		}
+
+		if *format == "" {
+			*format = BundleFormatJson
+			if strings.HasSuffix(strings.ToLower(*output), ".zip") {
+				*format = BundleFormatZip
+			}
+		}
+
+		if *format != BundleFormatJson && *format != BundleFormatZip {
+			return usageError(fmt.Sprintf("unsupported format %q", *format))
+		}
+
+		refs, err := x.resolveConfigRefs(fs.Args())
+		if err != nil {
+			return err
+		}
+
+		if err := x.unlockSecrets(); err != nil {
+			return err
+		}
+
+		bundle, err := x.configManager.ExportBundle(refs, !*include)
+		if err != nil {
+			return err
+		}
+
+		buf := new(bytes.Buffer)
+		if err := WriteBundle(buf, *format, bundle); err != nil {
+			return err
+		}
+
+		if *output == "" {
+			_, err = x.option.Stdout.Write(buf.Bytes())
+			return err
+		}
+
+		if err := os.WriteFile(*output, buf.Bytes(), 0600); err != nil {
+			return err
+		}
+		fmt.Fprintf(x.option.Stdout, "%d config(s) exported to %s\n", len(bundle.Configs), *output)
+		return nil
+
+	case "import":
+		fs := x.newFlagSet("bundle import")
+		conflict := fs.String("conflict", ConflictSkip, "what to do if a config already exists: skip, overwrite or rename")
+		if err := x.parseFlags(fs, args[1:]); err != nil {
+			return err
+		}
+
+		if fs.NArg() != 1 {
+			return usageError("bundle import requires exactly one bundle file")
+		}
+
+		content, err := os.ReadFile(fs.Arg(0))
+		if err != nil {
+			return err
+		}
+
+		bundle, err := ReadBundle(content)
+		if err != nil {
+			return err
+		}
+
+		if err := x.unlockSecrets(); err != nil {
+			return err
+		}
+
+		res, err := x.configManager.ImportBundle(bundle, *conflict)
+		if res != nil {
+			x.printBundleImportResult(res)
+		}
+		return err
+
+	default:
+		return usageError(fmt.Sprintf("unknown bundle command %q", args[0]))
+	}
+}
+
+// 将命令行中的 NAME 或 NAME/KEY 转换为配置的列表，未给定时返回所有 [Client] 的所有配置。
+func (x *command) resolveConfigRefs(args []string) ([]ConfigRef, error) {
+	if len(args) == 0 {
+		for _, c := range x.option.Clients {
+			args = append(args, c.Name())
+		}
+	}
+
+	var refs []ConfigRef
+	for _, arg := range args {
+		if !strings.Contains(arg, "/") {
+			c, err := x.findClient(arg)
+			if err != nil {
+				return nil, err
+			}
+
+			keys, err := x.configManager.ListKeys(c.Name())
+			if err != nil {
+				return nil, err
+			}
+
+			for _, key := range keys {
+				refs = append(refs, ConfigRef{Client: c.Name(), Key: key})
+			}
+			continue
+		}
+
+		ref, err := ParseConfigRef(arg)
+		if err != nil {
+			return nil, usageError(err.Error())
+		}
+
+		if _, err := x.findClient(ref.Client); err != nil {
+			return nil, err
+		}
+		refs = append(refs, ref)
+	}
+
+	return refs, nil
+}
+
+func (x *command) printBundleImportResult(res *BundleImportResult) {
+	for _, v := range res.Imported {
+		fmt.Fprintf(x.option.Stdout, "imported: %s\n", v)
+	}
+
+	for _, v := range res.Skipped {
+		fmt.Fprintf(x.option.Stdout, "skipped: %s\n", v)
+	}
+
+	renamed := make([]string, 0, len(res.Renamed))
+	for k := range res.Renamed {
+		renamed = append(renamed, k)
+	}
+	sort.Strings(renamed)
+	for _, k := range renamed {
+		fmt.Fprintf(x.option.Stdout, "renamed: %s -> %s\n", k, res.Renamed[k])
+	}
+}
+
+// 启用加密存储时解锁，未启用时不做任何事。口令从环境变量 [PassphraseEnvName] 读取，
+// 未给定口令时保持锁定，此时读写加密的字段会返回 [ErrSecretsLocked] 类别的错误。
+func (x *command) unlockSecrets() error {
+	mode, err := x.configManager.SecretMode()
+	if err != nil || mode == SecretModeNone {
+		return err
+	}
+
+	passphrase := os.Getenv(PassphraseEnvName)
+	if mode == SecretModePassphrase && passphrase == "" {
+		return nil
+	}
+	return x.configManager.UnlockSecrets(passphrase)
+}
+
+// 查找 [Client] 并应用已保存的配置；给定 envName 时，一并应用该环境的变量。
+// 同时加载全局默认的连接参数。
+func (x *command) loadClient(clientName, configKey, envName string) (Client, error) {
+	if clientName == "" || configKey == "" {
+		return nil, usageError("both -client and -config must be specified")
+	}
+
+	c, err := x.findClient(clientName)
+	if err != nil {
+		return nil, err
+	}
+
+	transportDefaults, err := x.configManager.LoadTransportDefaults()
+	if err != nil {
+		return nil, err
+	}
+	SetDefaultTransportOption(transportDefaults)
+
+	if err := x.unlockSecrets(); err != nil {
+		return nil, err
+	}
+
+	conf, err := x.configManager.Load(c.Name(), configKey)
+	if err != nil {
+		return nil, err
+	}
+	c.SetConfig(conf)
+
+	if envName != "" {
+		setter, ok := c.(VariableSetter)
+		if !ok {
+			return nil, fmt.Errorf("client %q does not support environments", c.Name())
+		}
+
+		vars, err := x.loadVariables(c.Name(), envName)
+		if err != nil {
+			return nil, err
+		}
+		setter.SetVariables(vars)
+	}
+
+	return c, nil
+}
+
+// 读取给定环境的变量， envName 为空时返回 nil 。
+func (x *command) loadVariables(clientName, envName string) (map[string]string, error) {
+	if envName == "" {
+		return nil, nil
+	}
+
+	envs, err := x.configManager.LoadEnvironments(clientName)
+	if err != nil {
+		return nil, err
+	}
+
+	vars, ok := envs[envName]
+	if !ok {
+		return nil, fmt.Errorf("environment %q not found", envName)
+	}
+	return vars, nil
+}
+
+func (x *command) findClient(name string) (Client, error) {
+	for _, c := range x.option.Clients {
+		if c.Name() == name {
+			return c, nil
+		}
+	}
+	return nil, usageError(fmt.Sprintf("unknown client %q", name))
+}
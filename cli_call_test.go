@@ -0,0 +1,53 @@
+package client_test
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	client "github.com/cmstar/go-webapi-client"
+	"github.com/cmstar/go-webapi-client/slimauth_client"
+	"github.com/stretchr/testify/require"
+)
+
+// 使用真实的 [client.Executor] 测试 call 子命令对响应状态码的处理。
+func TestRunCommand_CallStatus(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.RawQuery == "fail" {
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(`{"Code":500}`))
+			return
+		}
+		w.Write([]byte(`{"Code":0}`))
+	}))
+	defer ts.Close()
+
+	dir := t.TempDir()
+	m := client.NewConfigManager(dir)
+	r := require.New(t)
+	r.NoError(m.Save("SlimAuth", "ok", map[string]any{"Key": "k", "Secret": "s", "Uri": ts.URL + "/?ok", "Param": "{}"}))
+	r.NoError(m.Save("SlimAuth", "fail", map[string]any{"Key": "k", "Secret": "s", "Uri": ts.URL + "/?fail", "Param": "{}"}))
+
+	run := func(key string) (code int, stdout, stderr string) {
+		outBuf := new(bytes.Buffer)
+		errBuf := new(bytes.Buffer)
+		code = client.RunCommand(context.Background(), &client.CommandOption{
+			ConfigPath: dir,
+			Clients:    []client.Client{slimauth_client.NewClient()},
+			Stdout:     outBuf,
+			Stderr:     errBuf,
+		}, []string{"call", "-client", "SlimAuth", "-config", key})
+		return code, outBuf.String(), errBuf.String()
+	}
+
+	code, out, _ := run("ok")
+	r.Equal(client.ExitOK, code)
+	r.Contains(out, `"Code": 0`)
+
+	code, out, errOut := run("fail")
+	r.Equal(client.ExitError, code)
+	r.Empty(out)
+	r.Contains(errOut, "500 Internal Server Error")
+}
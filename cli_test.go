@@ -0,0 +1,207 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"fyne.io/fyne/v2"
+	"github.com/stretchr/testify/require"
+)
+
+// 用于测试命令行模式的 [Client] ，执行时返回配置里的 Result ，或 Error 对应的错误。
+type fakeClient struct {
+	config map[string]any
+	vars   map[string]string
+}
+
+var (
+	_ Executor       = (*fakeClient)(nil)
+	_ VariableSetter = (*fakeClient)(nil)
+	_ Exporter       = (*fakeClient)(nil)
+	_ SecretMarker   = (*fakeClient)(nil)
+)
+
+func (x *fakeClient) Name() string                    { return "Fake" }
+func (x *fakeClient) Title() string                   { return "Fake" }
+func (x *fakeClient) Box() fyne.CanvasObject          { return nil }
+func (x *fakeClient) GetConfig() map[string]any       { return x.config }
+func (x *fakeClient) SetConfig(config map[string]any) { x.config = config }
+
+func (x *fakeClient) Execute(ctx context.Context) (string, error) {
+	if e, ok := x.config["Error"]; ok {
+		return "", errors.New(e.(string))
+	}
+	return ExpandVariables(x.config["Result"].(string), x.vars), nil
+}
+
+func (x *fakeClient) SetVariables(vars map[string]string) { x.vars = vars }
+
+func (x *fakeClient) ExportRequest(signed bool) (*ExportedRequest, error) {
+	req := &ExportedRequest{Method: "POST", URL: "http://localhost/", Header: http.Header{}}
+	if signed {
+		req.Header.Set("Authorization", "signed")
+	}
+	if token, ok := x.config["Token"].(string); ok {
+		req.Header.Set("X-Token", ExpandVariables(token, x.vars))
+	}
+	return req, nil
+}
+
+func (x *fakeClient) SecretKeys() []string { return []string{"Token"} }
+
+func TestRunCommand(t *testing.T) {
+	clearAllConfig()
+	m := NewConfigManager(_CONFIG_PATH)
+	r := require.New(t)
+
+	r.NoError(m.Save("Fake", "ok", map[string]any{"Result": "hello"}))
+	r.NoError(m.Save("Fake", "var", map[string]any{"Result": "hello {{name}}"}))
+	r.NoError(m.SaveEnvironment("Fake", "dev", map[string]string{"name": "dev"}))
+	r.NoError(m.Save("Fake", "fail", map[string]any{"Error": "boom"}))
+	r.NoError(m.Save("Fake", "token", map[string]any{"Result": "hello", "Token": "token-{{name}}"}))
+
+	run := func(args ...string) (code int, stdout, stderr string) {
+		outBuf := new(bytes.Buffer)
+		errBuf := new(bytes.Buffer)
+		code = RunCommand(context.Background(), &CommandOption{
+			ConfigPath: _CONFIG_PATH,
+			Clients:    []Client{&fakeClient{}},
+			Stdout:     outBuf,
+			Stderr:     errBuf,
+		}, args)
+		return code, outBuf.String(), errBuf.String()
+	}
+
+	t.Run("list", func(t *testing.T) {
+		code, out, _ := run("list")
+		r.Equal(ExitOK, code)
+		r.Equal("Fake\n", out)
+
+		code, out, _ = run("list", "-client", "Fake")
+		r.Equal(ExitOK, code)
+		r.Equal("fail\nok\ntoken\nvar\n", out)
+
+		code, _, _ = run("list", "-client", "NotExist")
+		r.Equal(ExitUsage, code)
+	})
+
+	t.Run("call", func(t *testing.T) {
+		code, out, _ := run("call", "-client", "Fake", "-config", "ok")
+		r.Equal(ExitOK, code)
+		r.Equal("hello\n", out)
+
+		code, out, errOut := run("call", "-client", "Fake", "-config", "fail")
+		r.Equal(ExitError, code)
+		r.Empty(out)
+		r.Equal("boom\n", errOut)
+
+		code, _, errOut = run("call", "-client", "Fake", "-config", "not-exist")
+		r.Equal(ExitError, code)
+		r.Contains(errOut, "not-exist")
+
+		code, _, _ = run("call", "-client", "Fake")
+		r.Equal(ExitUsage, code)
+
+		code, out, _ = run("call", "-client", "Fake", "-config", "var", "-env", "dev")
+		r.Equal(ExitOK, code)
+		r.Equal("hello dev\n", out)
+
+		code, _, errOut = run("call", "-client", "Fake", "-config", "var", "-env", "not-exist")
+		r.Equal(ExitError, code)
+		r.Equal("environment \"not-exist\" not found\n", errOut)
+	})
+
+	t.Run("export", func(t *testing.T) {
+		code, out, _ := run("export", "-client", "Fake", "-config", "ok")
+		r.Equal(ExitOK, code)
+		r.Equal("curl -X POST 'http://localhost/' \\\n  -H 'Authorization: signed'\n", out)
+
+		code, out, _ = run("export", "-client", "Fake", "-config", "ok", "-format", "httpie", "-unsigned")
+		r.Equal(ExitOK, code)
+		r.Equal("http POST 'http://localhost/'\n", out)
+
+		code, _, errOut := run("export", "-client", "Fake", "-config", "ok", "-format", "xml")
+		r.Equal(ExitUsage, code)
+		r.Equal("unsupported format \"xml\"\n", errOut)
+
+		code, out, _ = run("export", "-client", "Fake", "-config", "token", "-env", "dev", "-unsigned")
+		r.Equal(ExitOK, code)
+		r.Equal("curl -X POST 'http://localhost/' \\\n  -H 'X-Token: ******'\n", out)
+
+		code, out, _ = run("export", "-client", "Fake", "-config", "token", "-env", "dev", "-unsigned", "-reveal-secrets")
+		r.Equal(ExitOK, code)
+		r.Equal("curl -X POST 'http://localhost/' \\\n  -H 'X-Token: token-dev'\n", out)
+	})
+
+	t.Run("secrets", func(t *testing.T) {
+		code, out, _ := run("secrets", "status")
+		r.Equal(ExitOK, code)
+		r.Equal("disabled\n", out)
+
+		code, out, _ = run("secrets", "enable", "-mode", "file")
+		r.Equal(ExitOK, code)
+		r.Equal("file\n", out)
+
+		code, out, _ = run("secrets", "status")
+		r.Equal(ExitOK, code)
+		r.Equal("file, unlocked\n", out)
+
+		code, out, _ = run("secrets", "migrate")
+		r.Equal(ExitOK, code)
+		r.Equal("Fake: 1 config(s) encrypted\n", out)
+
+		code, _, _ = run("secrets")
+		r.Equal(ExitUsage, code)
+	})
+
+	t.Run("bundle", func(t *testing.T) {
+		file := _CONFIG_PATH + "/bundle.zip"
+		code, out, _ := run("bundle", "export", "-o", file, "Fake/ok", "Fake/token")
+		r.Equal(ExitOK, code)
+		r.Equal("2 config(s) exported to "+file+"\n", out)
+
+		code, out, _ = run("bundle", "import", file)
+		r.Equal(ExitOK, code)
+		r.Equal("skipped: Fake/ok\nskipped: Fake/token\n", out)
+
+		code, out, _ = run("bundle", "import", "-conflict", "rename", file)
+		r.Equal(ExitOK, code)
+		r.Equal("renamed: Fake/ok -> Fake/ok (1)\nrenamed: Fake/token -> Fake/token (1)\n", out)
+
+		conf, err := m.Load("Fake", "token (1)")
+		r.NoError(err)
+		r.Equal("", conf["Token"])
+
+		code, out, _ = run("bundle", "export", "Fake")
+		r.Equal(ExitOK, code)
+		r.Contains(out, `"Key": "var"`)
+		r.NotContains(out, "token-{{name}}")
+
+		code, out, _ = run("bundle", "export", "-include-secrets", "Fake/token")
+		r.Equal(ExitOK, code)
+		r.Contains(out, "token-{{name}}")
+
+		code, _, _ = run("bundle", "export", "NotExist/a")
+		r.Equal(ExitUsage, code)
+
+		code, _, _ = run("bundle", "import")
+		r.Equal(ExitUsage, code)
+
+		code, _, _ = run("bundle")
+		r.Equal(ExitUsage, code)
+	})
+
+	t.Run("usage", func(t *testing.T) {
+		code, _, _ := run()
+		r.Equal(ExitUsage, code)
+
+		code, _, _ = run("unknown")
+		r.Equal(ExitUsage, code)
+
+		code, _, _ = run("list", "-bad-flag")
+		r.Equal(ExitUsage, code)
+	})
+}
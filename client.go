@@ -3,8 +3,10 @@
 package client
 
 import (
+	"context"
 	"flag"
 	"os"
+	"os/signal"
 	"path"
 
 	"fyne.io/fyne/v2"
@@ -24,7 +26,8 @@ type Client interface {
 	// 界面展示的标题。
 	Title() string
 
-	// 读取当前界面的元素。
+	// 读取当前界面的元素。每次切换到此 [Client] 时都会被调用，应返回同一个界面，
+	// 否则界面中注册在数据绑定上的监听器会不断累积。
 	Box() fyne.CanvasObject
 
 	// 读取当前界面的配置。用于配置的保存和读取功能。
@@ -51,10 +54,25 @@ func GetDefaultConfigDir() string {
 //
 // 会从程序的启动参数中读取下列参数，其余参数均使用默认值：
 //   - -c 指定配置文件的存储目录。
+//
+// 若参数后跟有子命令，如 list 、 call ，则不展示窗体，以命令行模式运行，完成后退出进程，
+// 详见 [RunCommand] 。
 func RunClients(clients []Client) {
 	configPath := flag.String("c", "", "specify the directory of config files")
 	flag.Parse()
 
+	if flag.NArg() > 0 {
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		code := RunCommand(ctx, &CommandOption{
+			ConfigPath: *configPath,
+			Clients:    clients,
+			Stdout:     os.Stdout,
+			Stderr:     os.Stderr,
+		}, flag.Args())
+		stop()
+		os.Exit(code)
+	}
+
 	op := &MainWindowOption{
 		ConfigPath: *configPath,
 		Clients:    clients,
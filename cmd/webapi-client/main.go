@@ -2,11 +2,15 @@ package main
 
 import (
 	client "github.com/cmstar/go-webapi-client"
+	"github.com/cmstar/go-webapi-client/http_client"
+	"github.com/cmstar/go-webapi-client/slimapi_client"
 	"github.com/cmstar/go-webapi-client/slimauth_client"
 )
 
 func main() {
 	client.RunClients([]client.Client{
 		slimauth_client.NewClient(),
+		slimapi_client.NewClient(),
+		http_client.NewClient(),
 	})
 }
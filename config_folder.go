@@ -0,0 +1,153 @@
+package client
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"sort"
+	"strings"
+)
+
+/*
+配置的目录（ folder ）。
+
+目录是 Client 目录下的子目录，可以多级嵌套，以 / 分隔的相对路径表示，如 a/b ；空字符串表示 Client 目录本身。
+目录中的配置的 key 带有目录的路径，如 a/b/key ，见 [ConfigManager] 。目录名的校验规则同 key 。
+*/
+
+// 返回配置 key 所在的目录和去掉目录后的名称。如 a/b/key 返回 a/b 和 key ；不在目录中时，目录为空字符串。
+func SplitConfigKey(key string) (folder, name string) {
+	i := strings.LastIndex(key, "/")
+	if i < 0 {
+		return "", key
+	}
+	return key[:i], key[i+1:]
+}
+
+// 返回目录 folder 下名称为 name 的配置的 key ，是 [SplitConfigKey] 的反向操作。
+func JoinConfigKey(folder, name string) string {
+	if folder == "" {
+		return name
+	}
+	return folder + "/" + name
+}
+
+// 返回 clientName 下所有的目录，包括空目录，按字典顺序排列。
+//
+// clientName 子目录不存在时，返回 nil 。
+func (x *ConfigManager) ListFolders(clientName string) ([]string, error) {
+	p, err := x.getClientDirPath(clientName)
+	if err != nil {
+		return nil, x.newError("list-folders", clientName, err)
+	}
+
+	var folders []string
+	err = x.walkClientDir(p, func(rel string, d fs.DirEntry) {
+		if d.IsDir() {
+			folders = append(folders, rel)
+		}
+	})
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, x.newError("list-folders", clientName, err)
+	}
+
+	sort.Strings(folders)
+	return folders, nil
+}
+
+// 创建目录，上级目录不存在时一并创建。若目录已存在，操作被忽略。
+func (x *ConfigManager) CreateFolder(clientName, folder string) error {
+	name := clientName + "/" + folder
+	p, err := x.getFolderPath(clientName, folder)
+	if err != nil {
+		return x.newError("create-folder", name, err)
+	}
+
+	err = os.MkdirAll(p, 0755)
+	if err != nil {
+		return x.newError("create-folder", name, err)
+	}
+	return nil
+}
+
+// 移除目录及其中所有的配置和子目录。若目录不存在，操作被忽略。
+func (x *ConfigManager) RemoveFolder(clientName, folder string) error {
+	name := clientName + "/" + folder
+	p, err := x.getFolderPath(clientName, folder)
+	if err != nil {
+		return x.newError("remove-folder", name, err)
+	}
+
+	err = os.RemoveAll(p)
+	if err != nil {
+		return x.newError("remove-folder", name, err)
+	}
+	return nil
+}
+
+// 将配置移动到目录 folder 下，名称不变， folder 为空字符串时移动到 Client 目录下；目标目录不存在时被创建出来。
+// 返回移动后的 key 。若目标位置已有同名配置，返回 [ErrConfigExists] 类别的错误。
+func (x *ConfigManager) Move(clientName, key, folder string) (string, error) {
+	_, base := SplitConfigKey(key)
+	target := JoinConfigKey(folder, base)
+	name := clientName + "/" + key
+
+	from, err := x.getKeyFilePath(clientName, key)
+	if err != nil {
+		return "", x.newError("move", name, err)
+	}
+
+	to, err := x.getKeyFilePath(clientName, target)
+	if err != nil {
+		return "", x.newError("move", name, err)
+	}
+
+	if err := x.rename(from, to, false); err != nil {
+		return "", x.newError("move", name, err)
+	}
+	return target, nil
+}
+
+// 将目录 folder 连同其中的配置移动到目录 parent 下，名称不变， parent 为空字符串时移动到 Client 目录下。
+// 返回移动后的目录。不能将目录移动到其自身或其子目录下；若目标位置已有同名目录，返回 [ErrConfigExists] 类别的错误。
+func (x *ConfigManager) MoveFolder(clientName, folder, parent string) (string, error) {
+	_, base := SplitConfigKey(folder)
+	target := JoinConfigKey(parent, base)
+	name := clientName + "/" + folder
+
+	if parent == folder || strings.HasPrefix(parent, folder+"/") {
+		return "", x.newError("move-folder", name, fmt.Errorf("cannot move a folder into itself"))
+	}
+
+	from, err := x.getFolderPath(clientName, folder)
+	if err != nil {
+		return "", x.newError("move-folder", name, err)
+	}
+
+	to, err := x.getFolderPath(clientName, target)
+	if err != nil {
+		return "", x.newError("move-folder", name, err)
+	}
+
+	if err := x.rename(from, to, false); err != nil {
+		return "", x.newError("move-folder", name, err)
+	}
+	return target, nil
+}
+
+func (x *ConfigManager) getFolderPath(clientName, folder string) (string, error) {
+	if err := x.validateName(clientName); err != nil {
+		return "", err
+	}
+
+	if err := x.validateKey(folder); err != nil {
+		return "", err
+	}
+
+	res := path.Join(x.rootPath, clientName, folder)
+	return res, nil
+}
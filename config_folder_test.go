@@ -0,0 +1,128 @@
+package client
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitConfigKey(t *testing.T) {
+	r := require.New(t)
+
+	check := func(key, wantFolder, wantName string) {
+		folder, name := SplitConfigKey(key)
+		r.Equal(wantFolder, folder, key)
+		r.Equal(wantName, name, key)
+		r.Equal(key, JoinConfigKey(folder, name))
+	}
+
+	check("a", "", "a")
+	check("f/a", "f", "a")
+	check("f/g/a", "f/g", "a")
+}
+
+func TestConfigManager_Folders(t *testing.T) {
+	clearAllConfig()
+	m := NewConfigManager(_CONFIG_PATH)
+	r := require.New(t)
+
+	listKeys := func() []string {
+		keys, err := m.ListKeys("x")
+		r.NoError(err)
+		return keys
+	}
+
+	listFolders := func() []string {
+		folders, err := m.ListFolders("x")
+		r.NoError(err)
+		return folders
+	}
+
+	r.Empty(listFolders())
+
+	// 保存到目录下，目录被自动创建。
+	r.NoError(m.Save("x", "a", map[string]any{"v": "a"}))
+	r.NoError(m.Save("x", "f/b", map[string]any{"v": "b"}))
+	r.NoError(m.Save("x", "f/g/c", map[string]any{"v": "c"}))
+	r.NoError(m.CreateFolder("x", "empty"))
+	r.NoError(m.CreateFolder("x", "empty")) // Do nothing.
+	r.NoError(m.SaveEnvironment("x", "dev", nil))
+	r.Equal([]string{"a", "f/b", "f/g/c"}, listKeys())
+	r.Equal([]string{"empty", "f", "f/g"}, listFolders())
+
+	conf, err := m.Load("x", "f/g/c")
+	r.NoError(err)
+	r.Equal(map[string]any{"v": "c"}, conf)
+
+	// 名称无效的目录被忽略。
+	r.NoError(os.Mkdir(_CONFIG_PATH+"/x/bad:dir", 0755))
+	r.NoError(os.WriteFile(_CONFIG_PATH+"/x/bad:dir/d.json", []byte("{}"), 0600))
+	r.Equal([]string{"a", "f/b", "f/g/c"}, listKeys())
+
+	// Move
+	moved, err := m.Move("x", "a", "f/g")
+	r.NoError(err)
+	r.Equal("f/g/a", moved)
+	r.Equal([]string{"f/b", "f/g/a", "f/g/c"}, listKeys())
+
+	moved, err = m.Move("x", "f/g/a", "")
+	r.NoError(err)
+	r.Equal("a", moved)
+
+	moved, err = m.Move("x", "a", "new")
+	r.NoError(err)
+	r.Equal("new/a", moved)
+	r.Equal([]string{"empty", "f", "f/g", "new"}, listFolders())
+
+	r.NoError(m.Save("x", "b", nil))
+	_, err = m.Move("x", "b", "f")
+	r.ErrorIs(err, ErrConfigExists)
+
+	_, err = m.Move("x", "not-exist", "f")
+	r.ErrorIs(err, ErrConfigNotFound)
+
+	_, err = m.Move("x", "b", "bad:name")
+	r.ErrorIs(err, ErrInvalidName)
+
+	// MoveFolder
+	moved, err = m.MoveFolder("x", "f/g", "empty")
+	r.NoError(err)
+	r.Equal("empty/g", moved)
+	r.Equal([]string{"b", "empty/g/c", "f/b", "new/a"}, listKeys())
+
+	_, err = m.MoveFolder("x", "empty", "empty/g")
+	r.Error(err)
+
+	r.NoError(m.CreateFolder("x", "g"))
+	_, err = m.MoveFolder("x", "empty/g", "")
+	r.ErrorIs(err, ErrConfigExists)
+
+	// RemoveFolder
+	r.NoError(m.RemoveFolder("x", "empty"))
+	r.NoError(m.RemoveFolder("x", "not-exist")) // Do nothing.
+	r.Equal([]string{"b", "f/b", "new/a"}, listKeys())
+	r.Equal([]string{"f", "g", "new"}, listFolders())
+
+	r.ErrorIs(m.RemoveFolder("x", ""), ErrInvalidName)
+	r.ErrorIs(m.CreateFolder("x", "a/../b"), ErrInvalidName)
+
+	clearAllConfig()
+}
+
+func TestConfigTree(t *testing.T) {
+	r := require.New(t)
+
+	tree := newConfigTree([]string{"a", "f", "f/b", "f/g/c", "z/d"}, []string{"e", "f/g"})
+	r.Equal([]string{"e/", "f/", "z/", "a", "f"}, tree.children[""])
+	r.Equal([]string{"f/g/", "f/b"}, tree.children["f/"])
+	r.Equal([]string{"f/g/c"}, tree.children["f/g/"])
+	r.Empty(tree.children["e/"])
+	r.Equal([]string{"e", "f", "f/g", "z"}, tree.folders())
+
+	r.Equal("c", tree.label("f/g/c"))
+	r.Equal("g", tree.label("f/g/"))
+	r.True(configTreeIsFolder("f/g/"))
+	r.True(configTreeIsFolder(""))
+	r.False(configTreeIsFolder("f/g/c"))
+}
@@ -2,22 +2,60 @@ package client
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io/fs"
 	"os"
 	"path"
+	"path/filepath"
 	"sort"
 	"strings"
+	"sync"
 )
 
+// 配置读写过程中的错误分类。可通过 [errors.Is] 判断 [ConfigError] 属于哪一类。
+var (
+	ErrInvalidName      = errors.New("invalid name")      // 给定的 clientName 、 key 或目录名不是合法的文件名。
+	ErrConfigExists     = errors.New("config exists")     // 目标配置或目录已存在，如移动配置时。
+	ErrCorruptConfig    = errors.New("corrupt config")    // 配置文件不是合法的 JSON 。
+	ErrPermissionDenied = errors.New("permission denied") // 没有读写配置文件或目录的权限。
+	ErrConfigNotFound   = errors.New("config not found")  // 配置文件不存在。
+	ErrSecretsLocked    = errors.New("secrets locked")    // 已启用加密存储但尚未解锁，或口令错误，见 [ConfigManager.UnlockSecrets] 。
+)
+
+// 表示 [ConfigManager] 操作过程中发生的错误。
+type ConfigError struct {
+	Op   string // 出错的操作，如 load 、 save 。
+	Name string // 相关的名称，通常是 clientName/key 。
+	Kind error  // 错误的分类，是 ErrXxx 之一；无法归类时为 nil 。
+	Err  error  // 底层的错误。
+}
+
+func (e *ConfigError) Error() string {
+	return fmt.Sprintf("%s %s: %v", e.Op, e.Name, e.Err)
+}
+
+func (e *ConfigError) Unwrap() error {
+	return e.Err
+}
+
+// 使 [errors.Is] 可以通过 Kind 判断错误的分类。
+func (e *ConfigError) Is(target error) bool {
+	return e.Kind != nil && e.Kind == target
+}
+
 // 用于读写配置。
 //
 // 配置存放在 rootPath 所指向的目录下，每个 ClientName 一个子目录，
-// 每个 key 一个 .json 文件。
+// 每个 key 一个 .json 文件。配置可以放在 ClientName 下的多级目录（ folder ）中，
+// 此时 key 为以 / 分隔的相对路径，如 folder1/key3 ，其中的每一段都需是合法的文件名。
 //
 //	root
 //	|- ClientName1
 //	|  |- key1.json
 //	|  |- key2.json
+//	|  |- folder1
+//	|     |- key3.json
 //	|- ClientName2
 //	|  |- key1.json
 //	|  |- key2.json
@@ -25,26 +63,37 @@ import (
 // 注意：
 //   - key 可以在不同的 ClientName 下重复。
 //   - Windows 平台的文件名是大小写不敏感的；*nix 则是敏感的。
+//   - 所有方法返回的错误均为 [*ConfigError] 。
+//   - 配置文件的权限为 0600 ；启用加密存储后，敏感字段被加密保存，见 secrets.go 。
 type ConfigManager struct {
 	rootPath string
+
+	// 加密存储的状态，见 secrets.go 。
+	secrets struct {
+		mu     sync.RWMutex
+		cipher *secretCipher       // 解锁后的主密钥，未启用或未解锁时为 nil 。
+		keys   map[string][]string // 每个 Client 需要加密的字段，见 [SecretMarker] 。
+	}
+
+	// 搜索配置内容时使用的缓存，见 config_search.go 。
+	index struct {
+		mu      sync.Mutex
+		entries map[string]searchIndexEntry // key 为 clientName/key 。
+	}
 }
 
 // 创建一个 [ConfigManager] ，给定存放配置文件的根目录的路径。
 func NewConfigManager(rootPath string) *ConfigManager {
-	return &ConfigManager{rootPath}
+	return &ConfigManager{rootPath: rootPath}
 }
 
-// 返回一个 clientName 下的所有配置项的 key ，按字典顺序排列。
+// 返回一个 clientName 下的所有配置项的 key ，包括各级目录中的，按字典顺序排列。
 //
 // clientName 子目录不存在时，返回 nil ；无效的配置会被忽略。
-func (x *ConfigManager) ListKeys(clientName string) []string {
-	p := x.getClientDirPath(clientName)
-	files, err := os.ReadDir(p)
+func (x *ConfigManager) ListKeys(clientName string) ([]string, error) {
+	p, err := x.getClientDirPath(clientName)
 	if err != nil {
-		if os.IsNotExist(err) {
-			return nil
-		}
-		panic(err)
+		return nil, x.newError("list", clientName, err)
 	}
 
 	trimExt := func(name, ext string) string {
@@ -60,92 +109,337 @@ func (x *ConfigManager) ListKeys(clientName string) []string {
 		return name[:len(name)-len(ext)]
 	}
 
-	keys := make([]string, 0, len(files))
-	for _, v := range files {
-		key := trimExt(v.Name(), ".json")
-		if key == "" {
-			continue
+	var keys []string
+	err = x.walkClientDir(p, func(rel string, d fs.DirEntry) {
+		if d.IsDir() {
+			return
+		}
+
+		key := trimExt(rel, ".json")
+		if key == "" || strings.HasSuffix(key, "/") {
+			return
 		}
 		keys = append(keys, key)
+	})
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, x.newError("list", clientName, err)
 	}
 
-	// os.ReadDir 读取出来本来应该是排序好的，但 API 并没有这个保证，这里再排序一下。
+	// filepath.WalkDir 按字典顺序遍历，但目录中的文件排在目录名之后的配置之前，这里再排序一下。
 	sort.Strings(keys)
-	return keys
+	return keys, nil
+}
+
+// 遍历 Client 目录下的配置文件和目录， rel 为以 / 分隔的相对路径。名称无效的目录被跳过。
+func (x *ConfigManager) walkClientDir(root string, fn func(rel string, d fs.DirEntry)) error {
+	return filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if p == root {
+			return nil
+		}
+
+		rel, err := filepath.Rel(root, p)
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() && x.validateName(d.Name()) != nil {
+			return filepath.SkipDir
+		}
+
+		fn(filepath.ToSlash(rel), d)
+		return nil
+	})
 }
 
 // 读取一个 clientName 下指定 key 的配置的值。
-// 不会返回 nil ，若对应配置不存， 返回 nil 。
+// 若对应配置不存在，返回 [ErrConfigNotFound] 类别的错误；若文件内容为 JSON null ，返回 nil 。
 // 应先通过 ListKeys 获取相关的数据。
-func (x *ConfigManager) Load(clientName, key string) map[string]any {
-	p := x.getKeyFilePath(clientName, key)
+func (x *ConfigManager) Load(clientName, key string) (map[string]any, error) {
+	name := clientName + "/" + key
+	p, err := x.getKeyFilePath(clientName, key)
+	if err != nil {
+		return nil, x.newError("load", name, err)
+	}
+
 	content, err := os.ReadFile(p)
 	if err != nil {
-		if os.IsNotExist(err) {
-			return nil
-		}
-		panic(err)
+		return nil, x.newError("load", name, err)
 	}
 
 	var res map[string]any
 	err = json.Unmarshal(content, &res)
 	if err != nil {
-		panic(err)
+		return nil, x.newError("load", name, wrapCorrupt(err))
+	}
+
+	err = x.decryptSecrets(res)
+	if err != nil {
+		return nil, x.newError("load", name, err)
 	}
 
-	return res
+	return res, nil
 }
 
 // 保存一个配置。若 key 在 clientName 下的配置中已存在，则覆盖原配置。
 // 若配置目录或文件不存在，会被创建出来。
-func (x *ConfigManager) Save(clientName, key string, conf map[string]any) {
+func (x *ConfigManager) Save(clientName, key string, conf map[string]any) error {
+	name := clientName + "/" + key
+	p, err := x.getKeyFilePath(clientName, key)
+	if err != nil {
+		return x.newError("save", name, err)
+	}
+
 	// 确保目录存在。
-	dir := x.getClientDirPath(clientName)
-	err := os.MkdirAll(dir, 0755)
+	err = os.MkdirAll(path.Dir(p), 0755)
 	if err != nil && !os.IsExist(err) {
-		panic(err)
+		return x.newError("save", name, err)
+	}
+
+	conf, err = x.encryptSecrets(clientName, conf)
+	if err != nil {
+		return x.newError("save", name, err)
 	}
 
 	content, err := json.Marshal(conf)
 	if err != nil {
-		panic(err)
+		return x.newError("save", name, err)
 	}
 
-	p := x.getKeyFilePath(clientName, key)
-	err = os.WriteFile(p, content, 0644)
+	err = writeFileAtomic(p, content, 0600)
 	if err != nil {
-		panic(err)
+		return x.newError("save", name, err)
 	}
+	return nil
+}
+
+// 同 [ConfigManager.Save] ，但 key 对应的配置已存在且 overwrite 为 false 时，返回 [ErrConfigExists] 类别的错误。
+func (x *ConfigManager) SaveAs(clientName, key string, conf map[string]any, overwrite bool) error {
+	if !overwrite {
+		exists, err := x.Exists(clientName, key)
+		if err != nil {
+			return err
+		}
+
+		if exists {
+			return x.newError("save", clientName+"/"+key, fmt.Errorf("%w: %s", ErrConfigExists, key))
+		}
+	}
+	return x.Save(clientName, key, conf)
+}
+
+// 判断 clientName 下是否存在 key 对应的配置。
+func (x *ConfigManager) Exists(clientName, key string) (bool, error) {
+	name := clientName + "/" + key
+	p, err := x.getKeyFilePath(clientName, key)
+	if err != nil {
+		return false, x.newError("stat", name, err)
+	}
+
+	_, err = os.Stat(p)
+	switch {
+	case err == nil:
+		return true, nil
+	case os.IsNotExist(err):
+		return false, nil
+	default:
+		return false, x.newError("stat", name, err)
+	}
+}
+
+// 将配置 key 重命名为 newKey ， newKey 可以在另一个目录下，目录不存在时被创建出来。
+// 同一文件系统内，重命名是原子的。 newKey 已存在且 overwrite 为 false 时，返回 [ErrConfigExists] 类别的错误。
+func (x *ConfigManager) Rename(clientName, key, newKey string, overwrite bool) error {
+	name := clientName + "/" + key
+	from, err := x.getKeyFilePath(clientName, key)
+	if err != nil {
+		return x.newError("rename", name, err)
+	}
+
+	to, err := x.getKeyFilePath(clientName, newKey)
+	if err != nil {
+		return x.newError("rename", name, err)
+	}
+
+	if err := x.rename(from, to, overwrite); err != nil {
+		return x.newError("rename", name, err)
+	}
+	return nil
+}
+
+// 将配置 key 复制为 newKey 。文件按原样复制，因此加密存储未解锁时也可以复制含有敏感字段的配置。
+// newKey 已存在且 overwrite 为 false 时，返回 [ErrConfigExists] 类别的错误。
+func (x *ConfigManager) Duplicate(clientName, key, newKey string, overwrite bool) error {
+	name := clientName + "/" + key
+	from, err := x.getKeyFilePath(clientName, key)
+	if err != nil {
+		return x.newError("duplicate", name, err)
+	}
+
+	to, err := x.getKeyFilePath(clientName, newKey)
+	if err != nil {
+		return x.newError("duplicate", name, err)
+	}
+
+	content, err := os.ReadFile(from)
+	if err != nil {
+		return x.newError("duplicate", name, err)
+	}
+
+	if err := x.checkTarget(to, overwrite); err != nil {
+		return x.newError("duplicate", name, err)
+	}
+
+	if err := writeFileAtomic(to, content, 0600); err != nil {
+		return x.newError("duplicate", name, err)
+	}
+	return nil
 }
 
 // 移除 clientName 下指定 key 的配置。若配置不存在，操作被忽略。
-func (x *ConfigManager) Remove(clientName, key string) {
-	p := x.getKeyFilePath(clientName, key)
-	err := os.Remove(p)
+func (x *ConfigManager) Remove(clientName, key string) error {
+	name := clientName + "/" + key
+	p, err := x.getKeyFilePath(clientName, key)
+	if err != nil {
+		return x.newError("remove", name, err)
+	}
+
+	err = os.Remove(p)
 	if err != nil && !os.IsNotExist(err) {
-		panic(err)
+		return x.newError("remove", name, err)
+	}
+	return nil
+}
+
+// 重命名文件或目录， to 的上级目录不存在时被创建出来。
+// to 已存在且 overwrite 为 false 时返回 [ErrConfigExists] 。
+func (x *ConfigManager) rename(from, to string, overwrite bool) error {
+	if _, err := os.Stat(from); err != nil {
+		return err
+	}
+
+	if from == to {
+		return nil
+	}
+
+	if err := x.checkTarget(to, overwrite); err != nil {
+		return err
 	}
+	return os.Rename(from, to)
 }
 
-func (x *ConfigManager) getClientDirPath(clientName string) string {
-	x.mustBeValidName(clientName)
+// 检查写入的目标位置，并确保其上级目录存在。 p 已存在且 overwrite 为 false 时返回 [ErrConfigExists] 。
+func (x *ConfigManager) checkTarget(p string, overwrite bool) error {
+	if !overwrite {
+		if _, err := os.Stat(p); err == nil {
+			return fmt.Errorf("%w: %s", ErrConfigExists, p)
+		} else if !os.IsNotExist(err) {
+			return err
+		}
+	}
+
+	return os.MkdirAll(path.Dir(p), 0755)
+}
+
+// 先写入同一目录下的临时文件，再重命名为目标文件，使读取方不会看到写了一半的内容。
+func writeFileAtomic(p string, content []byte, perm os.FileMode) error {
+	f, err := os.CreateTemp(path.Dir(p), "."+path.Base(p)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	tmp := f.Name()
+
+	_, err = f.Write(content)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Chmod(tmp, perm)
+	}
+	if err == nil {
+		err = os.Rename(tmp, p)
+	}
+
+	if err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return nil
+}
+
+func (x *ConfigManager) getClientDirPath(clientName string) (string, error) {
+	if err := x.validateName(clientName); err != nil {
+		return "", err
+	}
 
 	res := path.Join(x.rootPath, clientName)
-	return res
+	return res, nil
 }
 
-func (x *ConfigManager) getKeyFilePath(clientName, key string) string {
-	x.mustBeValidName(clientName)
-	x.mustBeValidName(key)
+func (x *ConfigManager) getKeyFilePath(clientName, key string) (string, error) {
+	if err := x.validateName(clientName); err != nil {
+		return "", err
+	}
+
+	if err := x.validateKey(key); err != nil {
+		return "", err
+	}
 
 	res := path.Join(x.rootPath, clientName, key+".json")
-	return res
+	return res, nil
+}
+
+// 将底层错误包装为 [*ConfigError] ，并根据错误内容归类。
+func (x *ConfigManager) newError(op, name string, err error) *ConfigError {
+	var kind error
+	switch {
+	case errors.Is(err, ErrInvalidName):
+		kind = ErrInvalidName
+	case errors.Is(err, ErrCorruptConfig):
+		kind = ErrCorruptConfig
+	case errors.Is(err, ErrSecretsLocked):
+		kind = ErrSecretsLocked
+	case errors.Is(err, ErrConfigExists):
+		kind = ErrConfigExists
+	case os.IsNotExist(err):
+		kind = ErrConfigNotFound
+	case os.IsPermission(err):
+		kind = ErrPermissionDenied
+	}
+
+	return &ConfigError{Op: op, Name: name, Kind: kind, Err: err}
+}
+
+// 将 JSON 解析错误包装为 [ErrCorruptConfig] 类别。
+func wrapCorrupt(err error) error {
+	return fmt.Errorf("%w: %v", ErrCorruptConfig, err)
+}
+
+// 校验 key 或目录的路径，以 / 分隔，每一段都需是合法的文件名，见 validateName 。
+func (x *ConfigManager) validateKey(v string) error {
+	if len(v) == 0 {
+		return fmt.Errorf("%w: name cannot be empty", ErrInvalidName)
+	}
+
+	for _, part := range strings.Split(v, "/") {
+		if err := x.validateName(part); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 // 校验文件名的有效性，以 Windows 为准，它的限制比较多， Linux 只要求不要包含斜杠。
-func (x *ConfigManager) mustBeValidName(v string) {
+// 无效时返回的错误可通过 [errors.Is] 匹配 [ErrInvalidName] 。
+func (x *ConfigManager) validateName(v string) error {
 	if len(v) == 0 {
-		panic("name cannot be empty")
+		return fmt.Errorf("%w: name cannot be empty", ErrInvalidName)
 	}
 
 	onlyDot := true
@@ -159,7 +453,7 @@ func (x *ConfigManager) mustBeValidName(v string) {
 			c == '<' ||
 			c == '>' ||
 			c == '|' {
-			panic(fmt.Sprintf(`the given name %q is not a valid file name`, v))
+			return fmt.Errorf(`%w: the given name %q is not a valid file name`, ErrInvalidName, v)
 		}
 
 		if c != '.' {
@@ -168,6 +462,8 @@ func (x *ConfigManager) mustBeValidName(v string) {
 	}
 
 	if onlyDot {
-		panic(fmt.Sprintf(`the given name %s is a relative name`, v))
+		return fmt.Errorf(`%w: the given name %s is a relative name`, ErrInvalidName, v)
 	}
+
+	return nil
 }
@@ -35,37 +35,166 @@ func TestConfigManager(t *testing.T) {
 	m := NewConfigManager(_CONFIG_PATH)
 	r := require.New(t)
 
-	r.Empty(m.ListKeys("x"))
-	r.Empty(m.ListKeys("y"))
+	listKeys := func(clientName string) []string {
+		keys, err := m.ListKeys(clientName)
+		r.NoError(err)
+		return keys
+	}
+
+	load := func(clientName, key string) map[string]any {
+		conf, err := m.Load(clientName, key)
+		r.NoError(err)
+		return conf
+	}
+
+	r.Empty(listKeys("x"))
+	r.Empty(listKeys("y"))
 
 	// Save & ListKeys
-	m.Save("x", "c", map[string]any{"xc": "3"})
-	m.Save("x", "nil", nil)
-	m.Save("x", "empty", map[string]any{})
-	m.Save("x", "a", map[string]any{"xa": "1"})
-	r.Equal([]string{"a", "c", "empty", "nil"}, m.ListKeys("x"))
+	r.NoError(m.Save("x", "c", map[string]any{"xc": "3"}))
+	r.NoError(m.Save("x", "nil", nil))
+	r.NoError(m.Save("x", "empty", map[string]any{}))
+	r.NoError(m.Save("x", "a", map[string]any{"xa": "1"}))
+	r.Equal([]string{"a", "c", "empty", "nil"}, listKeys("x"))
 
-	m.Save("z", "a", map[string]any{"za": "2"})
-	m.Save("z", "b", map[string]any{"zb": "3"})
-	r.Equal([]string{"a", "b"}, m.ListKeys("z"))
+	r.NoError(m.Save("z", "a", map[string]any{"za": "2"}))
+	r.NoError(m.Save("z", "b", map[string]any{"zb": "3"}))
+	r.Equal([]string{"a", "b"}, listKeys("z"))
 
 	// Load
-	r.Equal(map[string]any{"xa": "1"}, m.Load("x", "a"))
-	r.Nil(m.Load("x", "nil"))
-	r.Equal(map[string]any{}, m.Load("x", "empty"))
-	r.Equal(map[string]any{"xc": "3"}, m.Load("x", "c"))
-	r.Equal(map[string]any{"za": "2"}, m.Load("z", "a"))
-	r.Equal(map[string]any{"zb": "3"}, m.Load("z", "b"))
-	r.Nil(m.Load("not-exist", "a"))
+	r.Equal(map[string]any{"xa": "1"}, load("x", "a"))
+	r.Nil(load("x", "nil"))
+	r.Equal(map[string]any{}, load("x", "empty"))
+	r.Equal(map[string]any{"xc": "3"}, load("x", "c"))
+	r.Equal(map[string]any{"za": "2"}, load("z", "a"))
+	r.Equal(map[string]any{"zb": "3"}, load("z", "b"))
+
+	_, err := m.Load("not-exist", "a")
+	r.ErrorIs(err, ErrConfigNotFound)
 
 	// Remove
-	m.Remove("not-exist", "a") // Do nothing.
+	r.NoError(m.Remove("not-exist", "a")) // Do nothing.
+
+	r.NoError(m.Remove("x", "a"))
+	r.Equal([]string{"c", "empty", "nil"}, listKeys("x"))
+	_, err = m.Load("x", "a")
+	r.ErrorIs(err, ErrConfigNotFound)
+
+	r.NoError(m.Remove("z", "b"))
+	r.Equal([]string{"a"}, listKeys("z"))
+	_, err = m.Load("z", "b")
+	r.ErrorIs(err, ErrConfigNotFound)
+}
+
+func TestConfigManager_Errors(t *testing.T) {
+	clearAllConfig()
+	m := NewConfigManager(_CONFIG_PATH)
+	r := require.New(t)
+
+	t.Run("InvalidName", func(t *testing.T) {
+		for _, name := range []string{"", ".", "..", "a/b", `a\b`, "a:b", "a*", "a?", `a"`, "<a>", "a|b"} {
+			_, err := m.Load(name, "a")
+			r.ErrorIs(err, ErrInvalidName, name)
+
+			_, err = m.ListKeys(name)
+			r.ErrorIs(err, ErrInvalidName, name)
+		}
+
+		// key 可以带有目录，但每一段都需是合法的文件名。
+		for _, name := range []string{"", ".", "..", "/a", "a/", "a//b", "a/../b", `a\b`, "a:b", "a*", "a?", `a"`, "<a>", "a|b"} {
+			err := m.Save("x", name, nil)
+			r.ErrorIs(err, ErrInvalidName, name)
 
-	m.Remove("x", "a")
-	r.Equal([]string{"c", "empty", "nil"}, m.ListKeys("x"))
-	r.Nil(m.Load("x", "a"))
+			err = m.Remove("x", name)
+			r.ErrorIs(err, ErrInvalidName, name)
+		}
+	})
 
-	m.Remove("z", "b")
-	r.Equal([]string{"a"}, m.ListKeys("z"))
-	r.Nil(m.Load("z", "b"))
+	t.Run("CorruptConfig", func(t *testing.T) {
+		r.NoError(m.Save("x", "bad", nil))
+		r.NoError(os.WriteFile(_CONFIG_PATH+"/x/bad.json", []byte("{bad"), 0644))
+
+		_, err := m.Load("x", "bad")
+		r.ErrorIs(err, ErrCorruptConfig)
+		r.NotErrorIs(err, ErrConfigNotFound)
+
+		var configErr *ConfigError
+		r.ErrorAs(err, &configErr)
+		r.Equal("load", configErr.Op)
+		r.Equal("x/bad", configErr.Name)
+	})
+}
+
+func TestConfigManager_RenameDuplicate(t *testing.T) {
+	clearAllConfig()
+	m := NewConfigManager(_CONFIG_PATH)
+	r := require.New(t)
+
+	listKeys := func() []string {
+		keys, err := m.ListKeys("x")
+		r.NoError(err)
+		return keys
+	}
+
+	load := func(key string) map[string]any {
+		conf, err := m.Load("x", key)
+		r.NoError(err)
+		return conf
+	}
+
+	// SaveAs & Exists
+	r.NoError(m.SaveAs("x", "a", map[string]any{"v": "a"}, false))
+	r.NoError(m.SaveAs("x", "b", map[string]any{"v": "b"}, false))
+	r.ErrorIs(m.SaveAs("x", "a", map[string]any{"v": "a2"}, false), ErrConfigExists)
+	r.Equal(map[string]any{"v": "a"}, load("a"))
+	r.NoError(m.SaveAs("x", "a", map[string]any{"v": "a2"}, true))
+	r.Equal(map[string]any{"v": "a2"}, load("a"))
+
+	exists, err := m.Exists("x", "a")
+	r.NoError(err)
+	r.True(exists)
+
+	exists, err = m.Exists("x", "not-exist")
+	r.NoError(err)
+	r.False(exists)
+
+	_, err = m.Exists("x", "a:b")
+	r.ErrorIs(err, ErrInvalidName)
+
+	// Rename
+	r.NoError(m.Rename("x", "a", "c", false))
+	r.Equal([]string{"b", "c"}, listKeys())
+	r.Equal(map[string]any{"v": "a2"}, load("c"))
+
+	r.NoError(m.Rename("x", "c", "f/c", false))
+	r.Equal([]string{"b", "f/c"}, listKeys())
+
+	r.ErrorIs(m.Rename("x", "b", "f/c", false), ErrConfigExists)
+	r.NoError(m.Rename("x", "b", "f/c", true))
+	r.Equal([]string{"f/c"}, listKeys())
+	r.Equal(map[string]any{"v": "b"}, load("f/c"))
+
+	r.ErrorIs(m.Rename("x", "not-exist", "d", false), ErrConfigNotFound)
+	r.ErrorIs(m.Rename("x", "f/c", "", false), ErrInvalidName)
+
+	// Duplicate
+	r.NoError(m.Duplicate("x", "f/c", "d", false))
+	r.Equal([]string{"d", "f/c"}, listKeys())
+	r.Equal(map[string]any{"v": "b"}, load("d"))
+
+	r.NoError(m.Save("x", "d", map[string]any{"v": "d"}))
+	r.ErrorIs(m.Duplicate("x", "d", "f/c", false), ErrConfigExists)
+	r.NoError(m.Duplicate("x", "d", "f/c", true))
+	r.Equal(map[string]any{"v": "d"}, load("f/c"))
+
+	r.ErrorIs(m.Duplicate("x", "not-exist", "e", false), ErrConfigNotFound)
+
+	// 不留下临时文件。
+	files, err := os.ReadDir(_CONFIG_PATH + "/x")
+	r.NoError(err)
+	for _, f := range files {
+		r.NotContains(f.Name(), ".tmp")
+	}
+
+	clearAllConfig()
 }
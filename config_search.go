@@ -0,0 +1,243 @@
+package client
+
+import (
+	"os"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+)
+
+/*
+配置的搜索。
+
+查询按空白拆分为多个词，每个词都需匹配：
+  - 配置的 key （含目录的路径）按模糊匹配，词中的字符按顺序出现即可，如 ordq 匹配 orders/query 。
+  - 给定 contents 时，词也可以匹配配置中的字符串值（如 URL 、方法名、参数），按不区分大小写的子串匹配。
+    内容很长，模糊匹配几乎总能成功，因此不用于内容。敏感字段（见 [SecretMarker] ）不参与匹配。
+
+配置的内容在首次搜索时通过 [ConfigManager.Load] 读取并缓存，文件的修改时间或大小变化后才重新读取，
+避免每次输入都读取所有的配置文件。读取失败（如加密存储未解锁）时不缓存，下次搜索时重试；
+已不存在的配置的缓存在搜索时被清除。
+*/
+
+// 搜索内容时缓存的配置。
+type searchIndexEntry struct {
+	modTime time.Time
+	size    int64
+	text    string // 配置中所有字符串值，已转为小写，以换行分隔。
+}
+
+// 搜索 clientName 下的配置，返回匹配的 key ，按匹配程度从高到低排列，程度相同时按字典顺序。
+// query 为空白时返回所有的 key 。
+func (x *ConfigManager) Search(clientName, query string, contents bool) ([]string, error) {
+	keys, err := x.ListKeys(clientName)
+	if err != nil {
+		return nil, err
+	}
+
+	x.pruneSearchIndex(clientName, keys)
+
+	words := strings.Fields(strings.ToLower(query))
+	if len(words) == 0 {
+		return keys, nil
+	}
+
+	type match struct {
+		key   string
+		score int
+	}
+
+	var matches []match
+	for _, key := range keys {
+		total := 0
+		var text string
+		textLoaded := false
+
+		ok := true
+		for _, w := range words {
+			if score, matched := fuzzyScore(w, key); matched {
+				total += score
+				continue
+			}
+
+			if !contents {
+				ok = false
+				break
+			}
+
+			if !textLoaded {
+				text, err = x.searchText(clientName, key)
+				if err != nil {
+					return nil, err
+				}
+				textLoaded = true
+			}
+
+			if !strings.Contains(text, w) {
+				ok = false
+				break
+			}
+		}
+
+		if ok {
+			matches = append(matches, match{key, total})
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].score > matches[j].score
+	})
+
+	res := make([]string, len(matches))
+	for i, m := range matches {
+		res[i] = m.key
+	}
+	return res, nil
+}
+
+// 返回用于搜索的配置内容，文件没有变化时使用缓存。
+// 无法读取的配置（如已损坏或加密存储未解锁）视为没有内容，且不被缓存，以便解锁或修复后能被搜到。
+func (x *ConfigManager) searchText(clientName, key string) (string, error) {
+	p, err := x.getKeyFilePath(clientName, key)
+	if err != nil {
+		return "", x.newError("search", clientName+"/"+key, err)
+	}
+
+	info, err := os.Stat(p)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", nil
+		}
+		return "", x.newError("search", clientName+"/"+key, err)
+	}
+
+	id := clientName + "/" + key
+	x.index.mu.Lock()
+	entry, ok := x.index.entries[id]
+	x.index.mu.Unlock()
+
+	if ok && entry.modTime.Equal(info.ModTime()) && entry.size == info.Size() {
+		return entry.text, nil
+	}
+
+	conf, err := x.Load(clientName, key)
+	if err != nil {
+		return "", nil
+	}
+
+	x.secrets.mu.RLock()
+	secretKeys := x.secrets.keys[clientName]
+	x.secrets.mu.RUnlock()
+
+	var b strings.Builder
+	for _, k := range sortedKeys(conf) {
+		if isSecretKey(k, secretKeys) {
+			continue
+		}
+		appendSearchText(&b, conf[k])
+	}
+
+	entry = searchIndexEntry{modTime: info.ModTime(), size: info.Size(), text: strings.ToLower(b.String())}
+	x.index.mu.Lock()
+	if x.index.entries == nil {
+		x.index.entries = make(map[string]searchIndexEntry)
+	}
+	x.index.entries[id] = entry
+	x.index.mu.Unlock()
+
+	return entry.text, nil
+}
+
+// 清除 clientName 下已不存在的配置的缓存， keys 是现有的配置。
+func (x *ConfigManager) pruneSearchIndex(clientName string, keys []string) {
+	exists := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		exists[clientName+"/"+key] = true
+	}
+
+	prefix := clientName + "/"
+	x.index.mu.Lock()
+	defer x.index.mu.Unlock()
+
+	for id := range x.index.entries {
+		if strings.HasPrefix(id, prefix) && !exists[id] {
+			delete(x.index.entries, id)
+		}
+	}
+}
+
+// 将 v 中的所有字符串值写入 b ，以换行分隔。
+func appendSearchText(b *strings.Builder, v any) {
+	switch vv := v.(type) {
+	case string:
+		b.WriteString(vv)
+		b.WriteByte('\n')
+	case map[string]any:
+		for _, k := range sortedKeys(vv) {
+			appendSearchText(b, vv[k])
+		}
+	case []any:
+		for _, item := range vv {
+			appendSearchText(b, item)
+		}
+	}
+}
+
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func isSecretKey(k string, secretKeys []string) bool {
+	for _, v := range secretKeys {
+		if v == k {
+			return true
+		}
+	}
+	return false
+}
+
+// 模糊匹配：判断 pattern 中的字符是否按顺序（不区分大小写）出现在 text 中，返回匹配程度。
+// 连续匹配和在单词开头（如 / 、 - 、 _ 之后）的匹配得分更高。
+func fuzzyScore(pattern, text string) (int, bool) {
+	p := []rune(strings.ToLower(pattern))
+	t := []rune(strings.ToLower(text))
+	if len(p) == 0 {
+		return 0, true
+	}
+
+	score := 0
+	pi := 0
+	last := -2
+	for ti := 0; ti < len(t) && pi < len(p); ti++ {
+		if t[ti] != p[pi] {
+			continue
+		}
+
+		score++
+		if ti == last+1 {
+			score += 3
+		}
+		if ti == 0 || !unicode.IsLetter(t[ti-1]) && !unicode.IsDigit(t[ti-1]) {
+			score += 2
+		}
+
+		last = ti
+		pi++
+	}
+
+	if pi < len(p) {
+		return 0, false
+	}
+
+	// 名称中直接包含 pattern 的最优先。
+	if strings.Contains(string(t), string(p)) {
+		score += 10
+	}
+	return score, true
+}
@@ -0,0 +1,112 @@
+package client
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFuzzyScore(t *testing.T) {
+	r := require.New(t)
+
+	_, ok := fuzzyScore("ordq", "orders/query")
+	r.True(ok)
+
+	_, ok = fuzzyScore("qo", "orders/query")
+	r.False(ok)
+
+	_, ok = fuzzyScore("", "a")
+	r.True(ok)
+
+	_, ok = fuzzyScore("ABC", "xaxbxc")
+	r.True(ok)
+
+	// 包含子串的优先；单词开头的优先。
+	s1, _ := fuzzyScore("query", "orders/query")
+	s2, _ := fuzzyScore("query", "q-u-e-r-y")
+	r.Greater(s1, s2)
+
+	s1, _ = fuzzyScore("oq", "orders/query")
+	s2, _ = fuzzyScore("oq", "foo-aqua")
+	r.Greater(s1, s2)
+}
+
+func TestConfigManager_Search(t *testing.T) {
+	clearAllConfig()
+	m := NewConfigManager(_CONFIG_PATH)
+	m.SetSecretKeys("x", []string{"Secret"})
+	r := require.New(t)
+
+	r.NoError(m.Save("x", "orders/query", map[string]any{"Uri": "http://temp.org/orders", "Secret": "hidden"}))
+	r.NoError(m.Save("x", "orders/create", map[string]any{"Uri": "http://temp.org/orders", "Param": `{"Name":"Alice"}`}))
+	r.NoError(m.Save("x", "users", map[string]any{"Uri": "http://temp.org/users", "Nested": map[string]any{"Items": []any{"Deep"}}}))
+	r.NoError(m.Save("x", "quick", nil))
+
+	search := func(query string, contents bool) []string {
+		keys, err := m.Search("x", query, contents)
+		r.NoError(err)
+		return keys
+	}
+
+	r.Equal([]string{"orders/create", "orders/query", "quick", "users"}, search(" ", false))
+	r.Equal([]string{"orders/query"}, search("ordq", false))
+	r.Equal([]string{"orders/create", "orders/query"}, search("orders", false))
+	r.Equal([]string{"orders/query", "quick"}, search("qu", false))
+	r.Empty(search("alice", false))
+
+	// 内容。
+	r.Equal([]string{"orders/create"}, search("alice", true))
+	r.Equal([]string{"orders/create"}, search("orders alice", true))
+	r.Equal([]string{"users"}, search("deep", true))
+	r.Empty(search("hidden", true))
+
+	// 文件修改后，索引被更新。
+	time.Sleep(10 * time.Millisecond)
+	r.NoError(m.Save("x", "users", map[string]any{"Uri": "http://temp.org/members"}))
+	r.Empty(search("deep", true))
+	r.Equal([]string{"users"}, search("members", true))
+
+	// 无法读取的配置视为没有内容。
+	r.NoError(os.WriteFile(_CONFIG_PATH+"/x/bad.json", []byte("{bad"), 0600))
+	r.Empty(search("bad-content", true))
+	r.Equal([]string{"bad"}, search("bad", true))
+
+	// 已删除的配置的缓存被清除。
+	r.Contains(m.index.entries, "x/users")
+	r.NoError(m.Remove("x", "users"))
+	r.Empty(search("members", true))
+	r.NotContains(m.index.entries, "x/users")
+
+	keys, err := m.Search("not-exist", "a", true)
+	r.NoError(err)
+	r.Empty(keys)
+
+	clearAllConfig()
+}
+
+func TestConfigManager_Search_Locked(t *testing.T) {
+	clearAllConfig()
+	r := require.New(t)
+
+	m := NewConfigManager(_CONFIG_PATH)
+	m.SetSecretKeys("x", []string{"Secret"})
+	_, err := m.EnableSecrets(SecretModePassphrase, "pass")
+	r.NoError(err)
+	r.NoError(m.Save("x", "a", map[string]any{"Uri": "http://temp.org/orders", "Secret": "s"}))
+
+	// 未解锁时读不到内容，解锁后可以搜到。
+	m = NewConfigManager(_CONFIG_PATH)
+	m.SetSecretKeys("x", []string{"Secret"})
+	keys, err := m.Search("x", "orders", true)
+	r.NoError(err)
+	r.Empty(keys)
+
+	r.NoError(m.UnlockSecrets("pass"))
+	keys, err = m.Search("x", "orders", true)
+	r.NoError(err)
+	r.Equal([]string{"a"}, keys)
+
+	clearAllConfig()
+}
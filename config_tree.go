@@ -0,0 +1,95 @@
+package client
+
+import (
+	"sort"
+	"strings"
+)
+
+// 配置列表的树形结构，用于 <ConfigList> 的 [widget.Tree] 。
+//
+// 节点的 ID ：根节点为空字符串；配置为其 key ；目录为其路径加上结尾的 / ，如 a/b/ ，
+// 以便与同名的配置区分（ a.json 和目录 a 可以同时存在）。
+type configTree struct {
+	children map[string][]string // 每个目录节点的子节点，目录在前，配置在后，各自按字典顺序排列。
+}
+
+func newConfigTree(keys, folders []string) *configTree {
+	tree := &configTree{children: map[string][]string{"": nil}}
+
+	// 确保目录及其各级上级目录都存在。
+	var addFolder func(folder string) string
+	addFolder = func(folder string) string {
+		if folder == "" {
+			return ""
+		}
+
+		id := configTreeFolderID(folder)
+		if _, ok := tree.children[id]; ok {
+			return id
+		}
+
+		parent, _ := SplitConfigKey(folder)
+		parentID := addFolder(parent)
+		tree.children[id] = nil
+		tree.children[parentID] = append(tree.children[parentID], id)
+		return id
+	}
+
+	for _, folder := range folders {
+		addFolder(folder)
+	}
+
+	for _, key := range keys {
+		folder, _ := SplitConfigKey(key)
+		parentID := addFolder(folder)
+		tree.children[parentID] = append(tree.children[parentID], key)
+	}
+
+	for _, ids := range tree.children {
+		sort.Slice(ids, func(i, j int) bool {
+			bi, bj := configTreeIsFolder(ids[i]), configTreeIsFolder(ids[j])
+			if bi != bj {
+				return bi
+			}
+			return ids[i] < ids[j]
+		})
+	}
+
+	return tree
+}
+
+// 返回节点的名称，即路径的最后一段。
+func (x *configTree) label(id string) string {
+	_, name := SplitConfigKey(strings.TrimSuffix(id, "/"))
+	return name
+}
+
+// 返回所有的目录，按字典顺序排列。
+func (x *configTree) folders() []string {
+	res := make([]string, 0, len(x.children))
+	for id := range x.children {
+		if id != "" {
+			res = append(res, configTreeFolderOf(id))
+		}
+	}
+	sort.Strings(res)
+	return res
+}
+
+// 返回目录对应的节点 ID 。
+func configTreeFolderID(folder string) string {
+	if folder == "" {
+		return ""
+	}
+	return folder + "/"
+}
+
+// 返回目录节点对应的目录。
+func configTreeFolderOf(id string) string {
+	return strings.TrimSuffix(id, "/")
+}
+
+// 判断节点是否为目录（含根节点）。
+func configTreeIsFolder(id string) bool {
+	return id == "" || strings.HasSuffix(id, "/")
+}
@@ -0,0 +1,180 @@
+package client
+
+import (
+	"context"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// 配置目录中的一处变化，见 [ConfigManager.Watch] 。
+type ConfigChange struct {
+	Client string // 发生变化的 Client 的名称。
+	Key    string // 发生变化的配置的 key ；变化的是目录（新建、删除、改名等）时为空字符串。
+}
+
+// 合并连续的文件事件的时间窗口。编辑器保存一个文件时通常会产生多个事件。
+const configWatchDelay = 200 * time.Millisecond
+
+// 监视配置目录，返回其中各 Client 的配置和目录的变化，包括由当前程序自身的保存操作引起的。
+// 一段时间内的多个事件会被合并，同一配置的变化只通知一次。
+//
+// 以 . 开头的文件（如请求历史、环境、保存时的临时文件）以及根目录下的文件的变化被忽略。
+// ctx 结束时停止监视，并关闭返回的 channel 。
+func (x *ConfigManager) Watch(ctx context.Context) (<-chan ConfigChange, error) {
+	err := os.MkdirAll(x.rootPath, 0755)
+	if err != nil {
+		return nil, x.newError("watch", x.rootPath, err)
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, x.newError("watch", x.rootPath, err)
+	}
+
+	err = x.watchDir(watcher, x.rootPath)
+	if err != nil {
+		watcher.Close()
+		return nil, x.newError("watch", x.rootPath, err)
+	}
+
+	ch := make(chan ConfigChange, 16)
+	go x.runWatcher(ctx, watcher, ch)
+	return ch, nil
+}
+
+func (x *ConfigManager) runWatcher(ctx context.Context, watcher *fsnotify.Watcher, ch chan<- ConfigChange) {
+	defer close(ch)
+	defer watcher.Close()
+
+	pending := make(map[ConfigChange]struct{})
+	timer := time.NewTimer(configWatchDelay)
+	timer.Stop()
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+
+		case ev, ok := <-watcher.Events:
+			if !ok {
+				return
+			}
+
+			change, ok := x.handleWatchEvent(watcher, ev)
+			if !ok {
+				continue
+			}
+
+			pending[change] = struct{}{}
+			timer.Reset(configWatchDelay)
+
+		case _, ok := <-watcher.Errors:
+			// 错误通常是事件队列溢出，此时无法得知具体的变化，忽略即可，下一个事件仍会触发刷新。
+			if !ok {
+				return
+			}
+
+		case <-timer.C:
+			changes := make([]ConfigChange, 0, len(pending))
+			for c := range pending {
+				changes = append(changes, c)
+			}
+			pending = make(map[ConfigChange]struct{})
+
+			sort.Slice(changes, func(i, j int) bool {
+				if changes[i].Client != changes[j].Client {
+					return changes[i].Client < changes[j].Client
+				}
+				return changes[i].Key < changes[j].Key
+			})
+
+			for _, c := range changes {
+				select {
+				case ch <- c:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}
+}
+
+// 将文件事件转换为 [ConfigChange] ，第二个返回值为 false 时事件应被忽略。
+// 新建的目录会被加入监视。
+func (x *ConfigManager) handleWatchEvent(watcher *fsnotify.Watcher, ev fsnotify.Event) (ConfigChange, bool) {
+	rel, err := filepath.Rel(x.rootPath, ev.Name)
+	if err != nil {
+		return ConfigChange{}, false
+	}
+
+	rel = filepath.ToSlash(rel)
+	if strings.HasPrefix(filepath.Base(rel), ".") {
+		return ConfigChange{}, false
+	}
+
+	clientName, rest, _ := strings.Cut(rel, "/")
+	if x.validateName(clientName) != nil {
+		return ConfigChange{}, false
+	}
+
+	// 新建的目录，连同其中已有的子目录（如通过移动得到的）一起监视。
+	if ev.Op&fsnotify.Create != 0 {
+		if info, err := os.Stat(ev.Name); err == nil && info.IsDir() {
+			x.watchDir(watcher, ev.Name)
+			return ConfigChange{Client: clientName}, true
+		}
+	}
+
+	// 根目录下只关心 Client 目录的删除和改名，忽略其中的文件，如 transport.json 。
+	if rest == "" {
+		if ev.Op&(fsnotify.Remove|fsnotify.Rename) == 0 || filepath.Ext(rel) != "" {
+			return ConfigChange{}, false
+		}
+		return ConfigChange{Client: clientName}, true
+	}
+
+	if ext := filepath.Ext(rest); strings.EqualFold(ext, ".json") {
+		key := rest[:len(rest)-len(ext)]
+		if x.validateKey(key) != nil {
+			return ConfigChange{}, false
+		}
+		return ConfigChange{Client: clientName, Key: key}, true
+	}
+
+	// 被删除或移走的可能是目录，已无法判断，按目录的变化通知。
+	if ev.Op&(fsnotify.Remove|fsnotify.Rename) != 0 && x.validateKey(rest) == nil {
+		return ConfigChange{Client: clientName}, true
+	}
+
+	return ConfigChange{}, false
+}
+
+// 监视目录 root 及其下名称有效的各级子目录。
+func (x *ConfigManager) watchDir(watcher *fsnotify.Watcher, root string) error {
+	return filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			// 目录可能在遍历时被删除。
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+
+		if !d.IsDir() {
+			return nil
+		}
+
+		if p != x.rootPath && x.validateName(d.Name()) != nil {
+			return filepath.SkipDir
+		}
+
+		return watcher.Add(p)
+	})
+}
@@ -0,0 +1,74 @@
+package client
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigManager_Watch(t *testing.T) {
+	clearAllConfig()
+	m := NewConfigManager(_CONFIG_PATH)
+	r := require.New(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	r.NoError(m.Save("x", "a", map[string]any{"v": "a"}))
+	ch, err := m.Watch(ctx)
+	r.NoError(err)
+
+	// 读取 channel 直到一段时间内没有新的变化。
+	receive := func() []ConfigChange {
+		var res []ConfigChange
+		for {
+			select {
+			case c, ok := <-ch:
+				r.True(ok)
+				res = append(res, c)
+			case <-time.After(3 * configWatchDelay):
+				return res
+			}
+		}
+	}
+
+	// 同一配置的多次写入只通知一次。
+	r.NoError(m.Save("x", "a", map[string]any{"v": "a1"}))
+	r.NoError(m.Save("x", "a", map[string]any{"v": "a2"}))
+	r.Equal([]ConfigChange{{Client: "x", Key: "a"}}, receive())
+
+	// 新建的目录被监视。
+	r.NoError(m.CreateFolder("x", "f"))
+	r.Equal([]ConfigChange{{Client: "x"}}, receive())
+	r.NoError(m.Save("x", "f/b", map[string]any{"v": "b"}))
+	r.Equal([]ConfigChange{{Client: "x", Key: "f/b"}}, receive())
+
+	// 新的 Client 目录。
+	r.NoError(m.Save("y", "c", map[string]any{"v": "c"}))
+	r.Contains(receive(), ConfigChange{Client: "y"})
+	r.NoError(m.Save("y", "c", map[string]any{"v": "c1"}))
+	r.Equal([]ConfigChange{{Client: "y", Key: "c"}}, receive())
+
+	r.NoError(m.Remove("x", "a"))
+	r.Equal([]ConfigChange{{Client: "x", Key: "a"}}, receive())
+
+	// 以 . 开头的文件和根目录下的文件被忽略。
+	r.NoError(m.SaveEnvironment("x", "dev", nil))
+	r.NoError(os.WriteFile(_CONFIG_PATH+"/x/f/.hidden.json", []byte("{}"), 0600))
+	r.NoError(os.WriteFile(_CONFIG_PATH+"/root.json", []byte("{}"), 0600))
+	r.Empty(receive())
+
+	// 结束时关闭 channel 。
+	cancel()
+	select {
+	case _, ok := <-ch:
+		r.False(ok)
+	case <-time.After(time.Second):
+		r.Fail("channel not closed")
+	}
+
+	clearAllConfig()
+}
@@ -0,0 +1,383 @@
+package client
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"unicode"
+)
+
+// 可选接口。 [Client] 实现此接口后，可从 curl 命令导入配置，见 [ParseCurl] 。
+type Importer interface {
+	// 判断请求是否使用该 Client 的协议，如 SlimAuth 检查 Authorization 头。用于导入时自动选择 Client 。
+	MatchRequest(req *CurlRequest) bool
+
+	// 将请求转换为该 Client 的配置，格式同 [Client.GetConfig] 。请求无法用该 Client 表示时返回 error 。
+	ImportRequest(req *CurlRequest) (map[string]any, error)
+}
+
+// 从 curl 命令中解析出的请求。
+type CurlRequest struct {
+	Method string      // HTTP 方法。未通过 -X 指定时，有 body 为 POST ，否则为 GET 。
+	URL    string      // 请求的地址。
+	Header http.Header // 请求头， -A 、 -b 、 -e 、 -u 等参数也被转换为对应的请求头。
+	Body   string      // 请求的 body ，多个 -d 参数以 & 连接。
+}
+
+// 返回 Authorization 头中的认证方案，如 SLIM-AUTH 、 Basic 。没有 Authorization 头时返回空字符串。
+func (x *CurlRequest) AuthScheme() string {
+	auth := strings.TrimSpace(x.Header.Get("Authorization"))
+	scheme, _, _ := strings.Cut(auth, " ")
+	return scheme
+}
+
+// 创建对应的 [http.Request] ，用于复用基于 [http.Request] 的解析逻辑，如读取 Authorization 头。
+func (x *CurlRequest) HttpRequest() (*http.Request, error) {
+	request, err := http.NewRequest(x.Method, x.URL, strings.NewReader(x.Body))
+	if err != nil {
+		return nil, err
+	}
+	request.Header = x.Header.Clone()
+	return request, nil
+}
+
+// curl 中不影响请求内容的参数，导入时被忽略。
+var curlIgnoredFlags = map[string]bool{
+	"-s": true, "--silent": true, "-S": true, "--show-error": true,
+	"-k": true, "--insecure": true, "-L": true, "--location": true,
+	"-v": true, "--verbose": true, "-i": true, "--include": true,
+	"--compressed": true, "-g": true, "--globoff": true,
+}
+
+// curl 中带有值、但不影响请求内容的参数，导入时被忽略。
+var curlIgnoredOptions = map[string]bool{
+	"-o": true, "--output": true, "-m": true, "--max-time": true,
+	"--connect-timeout": true, "-x": true, "--proxy": true,
+}
+
+// 解析一条 curl 命令，如从浏览器的“Copy as cURL”或日志中复制的命令。
+// 命令按 POSIX shell 的规则拆分，支持单引号、双引号、 $'...' 和行尾的 \ 续行。
+// 支持的参数： -X 、 -H 、 -d 及其变体（ --data-raw 、 --data-binary 等）、 --json 、 -G 、 -I 、
+// -A 、 -b 、 -e 、 -u 、 --url ；不影响请求内容的参数，如 -s 、 -k 、 --compressed 被忽略；其余参数视为错误。
+func ParseCurl(command string) (*CurlRequest, error) {
+	args, err := splitShellWords(command)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(args) > 0 && args[0] == "curl" {
+		args = args[1:]
+	}
+
+	req := &CurlRequest{Header: http.Header{}}
+	var data []string
+	var get, head bool
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+
+		// 取参数的值，支持 -XPOST 、 --request=POST 和独立的下一个参数三种形式。
+		name, value, hasValue := arg, "", false
+		switch {
+		case strings.HasPrefix(arg, "--"):
+			name, value, hasValue = strings.Cut(arg, "=")
+		case strings.HasPrefix(arg, "-") && len(arg) > 2:
+			name, value, hasValue = arg[:2], arg[2:], true
+		}
+
+		nextValue := func() (string, error) {
+			if hasValue {
+				return value, nil
+			}
+			if i+1 >= len(args) {
+				return "", fmt.Errorf("option %s requires a value", name)
+			}
+			i++
+			return args[i], nil
+		}
+
+		var v string
+		switch name {
+		case "-X", "--request", "-H", "--header", "-d", "--data", "--data-raw", "--data-ascii", "--data-binary",
+			"--data-urlencode", "--json", "-A", "--user-agent", "-b", "--cookie", "-e", "--referer", "-u", "--user", "--url":
+			v, err = nextValue()
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		switch name {
+		case "-X", "--request":
+			req.Method = strings.ToUpper(v)
+
+		case "-H", "--header":
+			k, hv, ok := strings.Cut(v, ":")
+			if !ok {
+				return nil, fmt.Errorf("invalid header %q", v)
+			}
+			req.Header.Add(strings.TrimSpace(k), strings.TrimSpace(hv))
+
+		case "-d", "--data", "--data-ascii", "--data-binary":
+			if strings.HasPrefix(v, "@") {
+				return nil, fmt.Errorf("reading data from a file is not supported: %s", v)
+			}
+			data = append(data, v)
+
+		case "--data-raw":
+			data = append(data, v)
+
+		case "--data-urlencode":
+			data = append(data, curlDataUrlencode(v))
+
+		case "--json":
+			data = append(data, v)
+			if req.Header.Get("Content-Type") == "" {
+				req.Header.Set("Content-Type", "application/json")
+			}
+			if req.Header.Get("Accept") == "" {
+				req.Header.Set("Accept", "application/json")
+			}
+
+		case "-A", "--user-agent":
+			req.Header.Set("User-Agent", v)
+
+		case "-b", "--cookie":
+			req.Header.Add("Cookie", v)
+
+		case "-e", "--referer":
+			req.Header.Set("Referer", v)
+
+		case "-u", "--user":
+			user, password, _ := strings.Cut(v, ":")
+			r, _ := http.NewRequest(http.MethodGet, "/", nil)
+			r.SetBasicAuth(user, password)
+			req.Header.Set("Authorization", r.Header.Get("Authorization"))
+
+		case "--url":
+			req.URL = v
+
+		case "-G", "--get":
+			get = true
+
+		case "-I", "--head":
+			head = true
+
+		default:
+			switch {
+			case isCurlIgnoredFlags(arg):
+			case curlIgnoredOptions[name]:
+				if _, err := nextValue(); err != nil {
+					return nil, err
+				}
+			case strings.HasPrefix(arg, "-") && arg != "-":
+				return nil, fmt.Errorf("unsupported option %s", name)
+			case req.URL != "":
+				return nil, fmt.Errorf("only one URL is supported: %s", arg)
+			default:
+				req.URL = arg
+			}
+		}
+	}
+
+	if req.URL == "" {
+		return nil, fmt.Errorf("the URL is missing")
+	}
+
+	// curl 允许省略协议，默认为 http 。
+	if !strings.Contains(req.URL, "://") {
+		req.URL = "http://" + req.URL
+	}
+
+	if _, err := url.Parse(req.URL); err != nil {
+		return nil, fmt.Errorf("invalid URL: %w", err)
+	}
+
+	body := strings.Join(data, "&")
+	switch {
+	case get:
+		// -G 将数据追加到 query string 上。
+		if body != "" {
+			sep := "?"
+			if strings.Contains(req.URL, "?") {
+				sep = "&"
+			}
+			req.URL += sep + body
+		}
+		if req.Method == "" {
+			req.Method = http.MethodGet
+		}
+
+	case head:
+		if req.Method == "" {
+			req.Method = http.MethodHead
+		}
+
+	default:
+		req.Body = body
+		if req.Method == "" {
+			if len(data) > 0 {
+				req.Method = http.MethodPost
+			} else {
+				req.Method = http.MethodGet
+			}
+		}
+
+		// 同 curl ， -d 未指定 Content-Type 时默认为 application/x-www-form-urlencoded 。
+		if len(data) > 0 && req.Header.Get("Content-Type") == "" {
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		}
+	}
+
+	return req, nil
+}
+
+// 判断参数是否是可忽略的开关，支持合并的短参数，如 -sSL 。
+func isCurlIgnoredFlags(arg string) bool {
+	if curlIgnoredFlags[arg] {
+		return true
+	}
+
+	if len(arg) < 3 || arg[0] != '-' || arg[1] == '-' {
+		return false
+	}
+
+	for _, c := range arg[1:] {
+		if !curlIgnoredFlags["-"+string(c)] {
+			return false
+		}
+	}
+	return true
+}
+
+// 按 curl 的规则处理 --data-urlencode 的值： "content" 、 "=content" 、 "name=content" 。
+func curlDataUrlencode(v string) string {
+	name, content, ok := strings.Cut(v, "=")
+	if !ok {
+		return url.QueryEscape(v)
+	}
+	if name == "" {
+		return url.QueryEscape(content)
+	}
+	return name + "=" + url.QueryEscape(content)
+}
+
+// 按 POSIX shell 的规则拆分命令行参数。支持单引号、双引号、 bash 的 $'...' 和行尾的 \ 续行，
+// 不支持变量、命令替换等 shell 的其他特性。
+func splitShellWords(s string) ([]string, error) {
+	var words []string
+	var word strings.Builder
+	inWord := false
+
+	runes := []rune(s)
+	for i := 0; i < len(runes); i++ {
+		c := runes[i]
+
+		switch {
+		case c == '\\':
+			if i+1 >= len(runes) {
+				return nil, fmt.Errorf("unexpected end of command after \\")
+			}
+			i++
+			// 续行： \ 后紧跟换行时，两者都被忽略。 Windows 的换行也一并处理。
+			if runes[i] == '\r' && i+1 < len(runes) && runes[i+1] == '\n' {
+				i++
+			}
+			if runes[i] == '\n' {
+				continue
+			}
+			word.WriteRune(runes[i])
+			inWord = true
+
+		case c == '\'':
+			end := indexRune(runes, i+1, '\'')
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated single quote")
+			}
+			word.WriteString(string(runes[i+1 : end]))
+			i = end
+			inWord = true
+
+		case c == '$' && i+1 < len(runes) && runes[i+1] == '\'':
+			n, err := readAnsiCQuoted(runes[i+2:], &word)
+			if err != nil {
+				return nil, err
+			}
+			i += 1 + n // 指向结尾的 ' 。
+			inWord = true
+
+		case c == '"':
+			i++
+			for ; i < len(runes) && runes[i] != '"'; i++ {
+				// 双引号内， \ 仅转义 $ ` " \ 和换行。
+				if runes[i] == '\\' && i+1 < len(runes) && strings.ContainsRune("$`\"\\\n", runes[i+1]) {
+					i++
+					if runes[i] == '\n' {
+						continue
+					}
+				}
+				word.WriteRune(runes[i])
+			}
+			if i >= len(runes) {
+				return nil, fmt.Errorf("unterminated double quote")
+			}
+			inWord = true
+
+		case unicode.IsSpace(c):
+			if inWord {
+				words = append(words, word.String())
+				word.Reset()
+				inWord = false
+			}
+
+		default:
+			word.WriteRune(c)
+			inWord = true
+		}
+	}
+
+	if inWord {
+		words = append(words, word.String())
+	}
+	return words, nil
+}
+
+// 读取 $'...' 的内容（不含开头的 $' ），写入 word ，返回读取的字符数（含结尾的 ' ）。
+func readAnsiCQuoted(runes []rune, word *strings.Builder) (int, error) {
+	escapes := map[rune]rune{
+		'n': '\n', 't': '\t', 'r': '\r', '\\': '\\', '\'': '\'', '"': '"', '?': '?',
+		'a': '\a', 'b': '\b', 'e': '\x1b', 'f': '\f', 'v': '\v',
+	}
+
+	for i := 0; i < len(runes); i++ {
+		switch c := runes[i]; c {
+		case '\'':
+			return i + 1, nil
+
+		case '\\':
+			if i+1 >= len(runes) {
+				return 0, fmt.Errorf("unterminated $'...'")
+			}
+			i++
+			if r, ok := escapes[runes[i]]; ok {
+				word.WriteRune(r)
+			} else {
+				word.WriteRune('\\')
+				word.WriteRune(runes[i])
+			}
+
+		default:
+			word.WriteRune(c)
+		}
+	}
+	return 0, fmt.Errorf("unterminated $'...'")
+}
+
+func indexRune(runes []rune, start int, r rune) int {
+	for i := start; i < len(runes); i++ {
+		if runes[i] == r {
+			return i
+		}
+	}
+	return -1
+}
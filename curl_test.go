@@ -0,0 +1,94 @@
+package client
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCurl(t *testing.T) {
+	t.Run("post", func(t *testing.T) {
+		r := require.New(t)
+		req, err := ParseCurl(`curl -X POST 'http://localhost/api?a=1' \
+  -H 'Content-Type: application/json' \
+  -H "Authorization: SLIM-AUTH Key=k, Sign=s, Timestamp=1, Version=1" \
+  --data-raw '{"s":"it'\''s"}' --compressed -sS`)
+		r.NoError(err)
+		r.Equal(http.MethodPost, req.Method)
+		r.Equal("http://localhost/api?a=1", req.URL)
+		r.Equal("application/json", req.Header.Get("Content-Type"))
+		r.Equal("SLIM-AUTH", req.AuthScheme())
+		r.Equal(`{"s":"it's"}`, req.Body)
+	})
+
+	t.Run("default-method", func(t *testing.T) {
+		r := require.New(t)
+		req, err := ParseCurl(`curl localhost:8080/x`)
+		r.NoError(err)
+		r.Equal(http.MethodGet, req.Method)
+		r.Equal("http://localhost:8080/x", req.URL)
+		r.Empty(req.Body)
+		r.Empty(req.AuthScheme())
+
+		req, err = ParseCurl(`curl http://localhost/ -d a=1 -d b=2`)
+		r.NoError(err)
+		r.Equal(http.MethodPost, req.Method)
+		r.Equal("a=1&b=2", req.Body)
+		r.Equal("application/x-www-form-urlencoded", req.Header.Get("Content-Type"))
+	})
+
+	t.Run("get-data", func(t *testing.T) {
+		r := require.New(t)
+		req, err := ParseCurl(`curl -G --url http://localhost/?a=1 --data-urlencode 'b=x y'`)
+		r.NoError(err)
+		r.Equal(http.MethodGet, req.Method)
+		r.Equal("http://localhost/?a=1&b=x+y", req.URL)
+		r.Empty(req.Body)
+	})
+
+	t.Run("attached-values", func(t *testing.T) {
+		r := require.New(t)
+		req, err := ParseCurl(`curl -XPUT --header=X-A:1 -AUA -u user:pass $'http://localhost/\x'`)
+		r.NoError(err)
+		r.Equal(http.MethodPut, req.Method)
+		r.Equal("1", req.Header.Get("X-A"))
+		r.Equal("UA", req.Header.Get("User-Agent"))
+		r.Equal("Basic", req.AuthScheme())
+		r.Equal(`http://localhost/\x`, req.URL)
+	})
+
+	t.Run("json", func(t *testing.T) {
+		r := require.New(t)
+		req, err := ParseCurl("curl --json $'{\"a\":\\n1}' http://localhost/")
+		r.NoError(err)
+		r.Equal(http.MethodPost, req.Method)
+		r.Equal("{\"a\":\n1}", req.Body)
+		r.Equal("application/json", req.Header.Get("Content-Type"))
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		r := require.New(t)
+		cases := map[string]string{
+			`curl`:                          "the URL is missing",
+			`curl 'http://localhost/`:       "unterminated single quote",
+			`curl "http://localhost/`:       "unterminated double quote",
+			`curl -H`:                       "option -H requires a value",
+			`curl -H bad http://localhost/`: `invalid header "bad"`,
+			`curl --unknown http://x/`:      "unsupported option --unknown",
+			`curl http://a/ http://b/`:      "only one URL is supported: http://b/",
+			`curl -d @file http://x/`:       "reading data from a file is not supported: @file",
+		}
+		for cmd, msg := range cases {
+			_, err := ParseCurl(cmd)
+			r.EqualError(err, msg, cmd)
+		}
+	})
+}
+
+func TestSplitShellWords(t *testing.T) {
+	r := require.New(t)
+	words, err := splitShellWords("a \"b \\\"c\\\" $x\" 'd \\e'\\\n f\\ g $'h\\ti'")
+	r.NoError(err)
+	r.Equal([]string{"a", `b "c" $x`, `d \e`, "f g", "h\ti"}, words)
+}
@@ -0,0 +1,182 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// 可选接口。 [Client] 实现此接口后，可以使用环境（ environment ）中定义的变量，
+// 在执行请求前，将各字段中的 {{name}} 替换为对应变量的值，参考 [ExpandVariables] 。
+type VariableSetter interface {
+	// 设置当前使用的变量。 vars 为 nil 表示不使用任何环境，此时各字段原样使用。
+	SetVariables(vars map[string]string)
+}
+
+// 存储环境的文件的名称，每个 Client 一个，位于 Client 的配置目录下。
+// 由于没有 .json 扩展名，不会被 [ConfigManager.ListKeys] 当作配置列出。
+const environmentFileName = ".environments"
+
+var variablePattern = regexp.MustCompile(`\{\{\s*([^{}\s]+)\s*\}\}`)
+
+// 将 s 中形如 {{name}} 的占位符替换为 vars 中对应的值，花括号内两侧的空白会被忽略。
+// vars 中未定义的变量保持原样。
+func ExpandVariables(s string, vars map[string]string) string {
+	if len(vars) == 0 || !strings.Contains(s, "{{") {
+		return s
+	}
+
+	return variablePattern.ReplaceAllStringFunc(s, func(m string) string {
+		name := variablePattern.FindStringSubmatch(m)[1]
+		if v, ok := vars[name]; ok {
+			return v
+		}
+		return m
+	})
+}
+
+// 读取一个 clientName 下的所有环境，返回值的 key 为环境的名称， value 为该环境的变量。
+// 尚未定义任何环境时，返回空的 map 。
+func (x *ConfigManager) LoadEnvironments(clientName string) (map[string]map[string]string, error) {
+	p, err := x.getEnvironmentFilePath(clientName)
+	if err != nil {
+		return nil, x.newError("load-env", clientName, err)
+	}
+
+	content, err := os.ReadFile(p)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return map[string]map[string]string{}, nil
+		}
+		return nil, x.newError("load-env", clientName, err)
+	}
+
+	res := make(map[string]map[string]string)
+	err = json.Unmarshal(content, &res)
+	if err != nil {
+		return nil, x.newError("load-env", clientName, wrapCorrupt(err))
+	}
+
+	return res, nil
+}
+
+// 返回一个 clientName 下所有环境的名称，按字典顺序排列。
+func (x *ConfigManager) ListEnvironments(clientName string) ([]string, error) {
+	envs, err := x.LoadEnvironments(clientName)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(envs))
+	for name := range envs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// 保存一个环境。若同名环境已存在，则覆盖。
+func (x *ConfigManager) SaveEnvironment(clientName, envName string, vars map[string]string) error {
+	if err := x.validateName(envName); err != nil {
+		return x.newError("save-env", clientName+"/"+envName, err)
+	}
+
+	envs, err := x.LoadEnvironments(clientName)
+	if err != nil {
+		return err
+	}
+
+	if vars == nil {
+		vars = map[string]string{}
+	}
+	envs[envName] = vars
+	return x.saveEnvironments(clientName, envs)
+}
+
+// 移除一个环境。若环境不存在，操作被忽略。
+func (x *ConfigManager) RemoveEnvironment(clientName, envName string) error {
+	envs, err := x.LoadEnvironments(clientName)
+	if err != nil {
+		return err
+	}
+
+	if _, ok := envs[envName]; !ok {
+		return nil
+	}
+
+	delete(envs, envName)
+	return x.saveEnvironments(clientName, envs)
+}
+
+func (x *ConfigManager) saveEnvironments(clientName string, envs map[string]map[string]string) error {
+	p, err := x.getEnvironmentFilePath(clientName)
+	if err != nil {
+		return x.newError("save-env", clientName, err)
+	}
+
+	err = os.MkdirAll(path.Dir(p), 0755)
+	if err != nil && !os.IsExist(err) {
+		return x.newError("save-env", clientName, err)
+	}
+
+	content, err := json.MarshalIndent(envs, "", "    ")
+	if err != nil {
+		return x.newError("save-env", clientName, err)
+	}
+
+	err = os.WriteFile(p, content, 0644)
+	if err != nil {
+		return x.newError("save-env", clientName, err)
+	}
+	return nil
+}
+
+func (x *ConfigManager) getEnvironmentFilePath(clientName string) (string, error) {
+	dir, err := x.getClientDirPath(clientName)
+	if err != nil {
+		return "", err
+	}
+	return path.Join(dir, environmentFileName), nil
+}
+
+// 将多行的 name=value 文本解析为变量。空行和以 # 开头的行被忽略， name 两侧的空白会被去掉。
+func parseVariables(text string) (map[string]string, error) {
+	vars := make(map[string]string)
+	for i, line := range strings.Split(text, "\n") {
+		line = strings.TrimRight(line, "\r")
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+
+		idx := strings.Index(line, "=")
+		if idx <= 0 || strings.TrimSpace(line[:idx]) == "" {
+			return nil, fmt.Errorf("line %d: the variable must be in the form name=value", i+1)
+		}
+
+		vars[strings.TrimSpace(line[:idx])] = line[idx+1:]
+	}
+	return vars, nil
+}
+
+// 将变量格式化为多行的 name=value 文本，按名称排序，是 [parseVariables] 的逆操作。
+func formatVariables(vars map[string]string) string {
+	names := make([]string, 0, len(vars))
+	for name := range vars {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	b := new(strings.Builder)
+	for _, name := range names {
+		b.WriteString(name)
+		b.WriteRune('=')
+		b.WriteString(vars[name])
+		b.WriteRune('\n')
+	}
+	return b.String()
+}
@@ -0,0 +1,80 @@
+package client
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpandVariables(t *testing.T) {
+	vars := map[string]string{"host": "temp.org", "id": "1"}
+
+	cases := []struct{ in, out string }{
+		{"", ""},
+		{"no vars", "no vars"},
+		{"http://{{host}}/api", "http://temp.org/api"},
+		{"{{ host }}:{{id}}{{id}}", "temp.org:11"},
+		{"{{undefined}}", "{{undefined}}"},
+		{"{host}} {{ }}", "{host}} {{ }}"},
+	}
+
+	for _, c := range cases {
+		require.Equal(t, c.out, ExpandVariables(c.in, vars), c.in)
+	}
+
+	require.Equal(t, "{{host}}", ExpandVariables("{{host}}", nil))
+}
+
+func TestParseVariables(t *testing.T) {
+	r := require.New(t)
+
+	vars, err := parseVariables("a=1\r\n\n# comment\n b =x=y\nc=\n")
+	r.NoError(err)
+	r.Equal(map[string]string{"a": "1", "b": "x=y", "c": ""}, vars)
+	r.Equal("a=1\nb=x=y\nc=\n", formatVariables(vars))
+
+	_, err = parseVariables("a=1\nbad")
+	r.EqualError(err, "line 2: the variable must be in the form name=value")
+
+	_, err = parseVariables(" =1")
+	r.Error(err)
+}
+
+func TestConfigManager_Environments(t *testing.T) {
+	clearAllConfig()
+	m := NewConfigManager(_CONFIG_PATH)
+	r := require.New(t)
+
+	envs, err := m.LoadEnvironments("x")
+	r.NoError(err)
+	r.Empty(envs)
+
+	r.NoError(m.SaveEnvironment("x", "dev", map[string]string{"host": "dev.temp.org"}))
+	r.NoError(m.SaveEnvironment("x", "prod", map[string]string{"host": "temp.org"}))
+	r.NoError(m.SaveEnvironment("x", "empty", nil))
+	r.ErrorIs(m.SaveEnvironment("x", "a/b", nil), ErrInvalidName)
+
+	names, err := m.ListEnvironments("x")
+	r.NoError(err)
+	r.Equal([]string{"dev", "empty", "prod"}, names)
+
+	envs, err = m.LoadEnvironments("x")
+	r.NoError(err)
+	r.Equal(map[string]map[string]string{
+		"dev":   {"host": "dev.temp.org"},
+		"prod":  {"host": "temp.org"},
+		"empty": {},
+	}, envs)
+
+	// 环境文件不应被当作配置列出。
+	r.NoError(m.Save("x", "a", map[string]any{}))
+	keys, err := m.ListKeys("x")
+	r.NoError(err)
+	r.Equal([]string{"a"}, keys)
+
+	r.NoError(m.RemoveEnvironment("x", "dev"))
+	r.NoError(m.RemoveEnvironment("x", "not-exist"))
+	names, err = m.ListEnvironments("x")
+	r.NoError(err)
+	r.Equal([]string{"empty", "prod"}, names)
+}
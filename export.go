@@ -0,0 +1,318 @@
+package client
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// 可选接口。 [Client] 实现此接口后，可将当前的配置导出为 curl 命令、 Go 代码等，见 [ExportSnippet] 。
+type Exporter interface {
+	// 根据当前的配置（含环境变量的替换）构建用于导出的请求。
+	// signed 为 false 时，不计算签名等依赖当前时间的内容，而是在 [ExportedRequest.GoSetup] 中给出生成它们的 Go 代码。
+	ExportRequest(signed bool) (*ExportedRequest, error)
+}
+
+// 导出的格式。
+const (
+	ExportFormatCurl   = "curl"   // curl 命令。
+	ExportFormatGo     = "go"     // 使用 net/http 的 Go 程序。
+	ExportFormatHttpie = "httpie" // HTTPie 命令。
+)
+
+// 所有支持的导出格式。
+var ExportFormats = []string{ExportFormatCurl, ExportFormatGo, ExportFormatHttpie}
+
+func isExportFormat(format string) bool {
+	for _, v := range ExportFormats {
+		if v == format {
+			return true
+		}
+	}
+	return false
+}
+
+// 描述一个用于导出的请求。
+type ExportedRequest struct {
+	Method string      // HTTP 方法。
+	URL    string      // 完整的请求地址。
+	Header http.Header // 请求头。
+	Body   string      // 请求的 body ，可以为空。
+
+	// 可选。 multipart/form-data 的字段，非空时代替 Body 导出，由导出的工具生成 body 和 Content-Type ，
+	// 文件字段引用文件的路径，而不是嵌入文件的内容。
+	Form []KeyValue
+
+	// 可选。生成 Go 代码时，插入在创建 req （ *http.Request ）之后、发送请求之前的代码，如计算签名。
+	GoSetup string
+
+	// GoSetup 所需的 import 路径。
+	GoImports []string
+}
+
+// 从 [http.Request] 创建 [ExportedRequest] ，会读取并替换 request.Body 。
+func NewExportedRequest(request *http.Request) (*ExportedRequest, error) {
+	res := &ExportedRequest{
+		Method: request.Method,
+		URL:    request.URL.String(),
+		Header: request.Header.Clone(),
+	}
+
+	if request.Body != nil {
+		body, err := io.ReadAll(request.Body)
+		request.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		request.Body = io.NopCloser(bytes.NewReader(body))
+		res.Body = string(body)
+	}
+
+	return res, nil
+}
+
+// 将请求导出为给定格式的代码片段， format 为 ExportFormatXxx 之一。
+func ExportSnippet(format string, req *ExportedRequest) (string, error) {
+	switch format {
+	case ExportFormatCurl:
+		return exportCurl(req), nil
+	case ExportFormatGo:
+		return exportGo(req), nil
+	case ExportFormatHttpie:
+		return exportHttpie(req), nil
+	default:
+		return "", fmt.Errorf("unsupported export format %q", format)
+	}
+}
+
+func exportCurl(req *ExportedRequest) string {
+	b := new(strings.Builder)
+	fmt.Fprintf(b, "curl -X %s %s", req.Method, shellQuote(req.URL))
+
+	for _, name := range req.headerNames() {
+		for _, v := range req.Header[name] {
+			fmt.Fprintf(b, " \\\n  -H %s", shellQuote(name+": "+v))
+		}
+	}
+
+	switch {
+	case len(req.Form) > 0:
+		// --form-string 不解析值中的 @ 、 < 和 ; 。
+		for _, kv := range req.Form {
+			if kv.File {
+				fmt.Fprintf(b, " \\\n  -F %s", shellQuote(kv.Name+"=@"+kv.Value))
+			} else {
+				fmt.Fprintf(b, " \\\n  --form-string %s", shellQuote(kv.Name+"="+kv.Value))
+			}
+		}
+
+	case req.Body != "":
+		fmt.Fprintf(b, " \\\n  --data-raw %s", shellQuote(req.Body))
+	}
+	return b.String()
+}
+
+func exportHttpie(req *ExportedRequest) string {
+	b := new(strings.Builder)
+	if len(req.Form) > 0 {
+		b.WriteString("http --multipart")
+	} else {
+		b.WriteString("http")
+	}
+	fmt.Fprintf(b, " %s %s", req.Method, shellQuote(req.URL))
+
+	for _, name := range req.headerNames() {
+		for _, v := range req.Header[name] {
+			fmt.Fprintf(b, " \\\n  %s", shellQuote(name+":"+v))
+		}
+	}
+
+	switch {
+	case len(req.Form) > 0:
+		for _, kv := range req.Form {
+			if kv.File {
+				fmt.Fprintf(b, " \\\n  %s", shellQuote(kv.Name+"@"+kv.Value))
+			} else {
+				fmt.Fprintf(b, " \\\n  %s", shellQuote(kv.Name+"="+kv.Value))
+			}
+		}
+
+	case req.Body != "":
+		// --raw 需要 HTTPie 3.0 以上的版本。
+		fmt.Fprintf(b, " \\\n  --raw %s", shellQuote(req.Body))
+	}
+	return b.String()
+}
+
+func exportGo(req *ExportedRequest) string {
+	imports := []string{"fmt", "io", "net/http"}
+	hasFile := false
+	switch {
+	case len(req.Form) > 0:
+		imports = append(imports, "bytes", "mime/multipart")
+		for _, kv := range req.Form {
+			hasFile = hasFile || kv.File
+		}
+		if hasFile {
+			imports = append(imports, "os", "path/filepath")
+		}
+
+	case req.Body != "":
+		imports = append(imports, "strings")
+	}
+	imports = append(imports, req.GoImports...)
+
+	b := new(strings.Builder)
+	b.WriteString("package main\n\nimport (\n")
+	writeGoImports(b, imports)
+	b.WriteString(")\n\nfunc main() {\n")
+
+	body := "nil"
+	switch {
+	case len(req.Form) > 0:
+		writeGoMultipart(b, req.Form, hasFile)
+		body = "body"
+
+	case req.Body != "":
+		fmt.Fprintf(b, "\tbody := strings.NewReader(%s)\n", goStringLiteral(req.Body))
+		body = "body"
+	}
+
+	fmt.Fprintf(b, "\treq, err := http.NewRequest(%s, %s, %s)\n", strconv.Quote(req.Method), strconv.Quote(req.URL), body)
+	b.WriteString("\tif err != nil {\n\t\tpanic(err)\n\t}\n")
+
+	for _, name := range req.headerNames() {
+		for _, v := range req.Header[name] {
+			fmt.Fprintf(b, "\treq.Header.Add(%s, %s)\n", strconv.Quote(name), strconv.Quote(v))
+		}
+	}
+
+	if len(req.Form) > 0 {
+		b.WriteString("\treq.Header.Set(\"Content-Type\", form.FormDataContentType())\n")
+	}
+
+	if req.GoSetup != "" {
+		b.WriteString("\n")
+		for _, line := range strings.Split(strings.TrimRight(req.GoSetup, "\n"), "\n") {
+			if line == "" {
+				b.WriteString("\n")
+			} else {
+				b.WriteString("\t" + line + "\n")
+			}
+		}
+	}
+
+	b.WriteString(`
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		panic(err)
+	}
+	defer res.Body.Close()
+
+	resBody, err := io.ReadAll(res.Body)
+	if err != nil {
+		panic(err)
+	}
+
+	fmt.Println(res.Status)
+	fmt.Println(string(resBody))
+}
+`)
+	return b.String()
+}
+
+// 输出将 fields 以 multipart/form-data 编码到变量 body 的 Go 代码， form 为其 *multipart.Writer 。
+func writeGoMultipart(b *strings.Builder, fields []KeyValue, hasFile bool) {
+	b.WriteString("\tbody := new(bytes.Buffer)\n")
+	b.WriteString("\tform := multipart.NewWriter(body)\n")
+
+	if hasFile {
+		b.WriteString(`	addFile := func(name, path string) {
+		file, err := os.Open(path)
+		if err != nil {
+			panic(err)
+		}
+		defer file.Close()
+
+		part, err := form.CreateFormFile(name, filepath.Base(path))
+		if err != nil {
+			panic(err)
+		}
+		if _, err := io.Copy(part, file); err != nil {
+			panic(err)
+		}
+	}
+`)
+	}
+
+	for _, kv := range fields {
+		if kv.File {
+			fmt.Fprintf(b, "\taddFile(%s, %s)\n", strconv.Quote(kv.Name), strconv.Quote(kv.Value))
+		} else {
+			fmt.Fprintf(b, "\tform.WriteField(%s, %s)\n", strconv.Quote(kv.Name), strconv.Quote(kv.Value))
+		}
+	}
+	b.WriteString("\tform.Close()\n\n")
+}
+
+// 按 gofmt 的习惯输出 import 列表：去重、排序，标准库在前，第三方库在后，中间空一行。
+func writeGoImports(b *strings.Builder, imports []string) {
+	seen := make(map[string]bool, len(imports))
+	var std, others []string
+	for _, v := range imports {
+		if seen[v] {
+			continue
+		}
+		seen[v] = true
+
+		if strings.Contains(strings.SplitN(v, "/", 2)[0], ".") {
+			others = append(others, v)
+		} else {
+			std = append(std, v)
+		}
+	}
+	sort.Strings(std)
+	sort.Strings(others)
+
+	for _, v := range std {
+		fmt.Fprintf(b, "\t%q\n", v)
+	}
+
+	if len(std) > 0 && len(others) > 0 {
+		b.WriteString("\n")
+	}
+
+	for _, v := range others {
+		fmt.Fprintf(b, "\t%q\n", v)
+	}
+}
+
+// 返回按名称排序的请求头。有 Form 时，忽略 Content-Type ，其值（含 boundary ）由导出的工具生成。
+func (x *ExportedRequest) headerNames() []string {
+	names := make([]string, 0, len(x.Header))
+	for name := range x.Header {
+		if len(x.Form) > 0 && strings.EqualFold(name, "Content-Type") {
+			continue
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// 用单引号包裹，使字符串在 POSIX shell 中按原文传递。
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// 字符串可以用反引号原样表示时使用反引号，如多行的 JSON ；否则使用双引号并转义。
+func goStringLiteral(s string) string {
+	if strconv.CanBackquote(strings.ReplaceAll(s, "\n", "")) {
+		return "`" + s + "`"
+	}
+	return strconv.Quote(s)
+}
@@ -0,0 +1,138 @@
+package client
+
+import (
+	"go/format"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewExportedRequest(t *testing.T) {
+	r := require.New(t)
+	request, err := http.NewRequest(http.MethodPost, "http://localhost/api?a=1", strings.NewReader(`{"a":1}`))
+	r.NoError(err)
+	request.Header.Set("Content-Type", "application/json")
+
+	req, err := NewExportedRequest(request)
+	r.NoError(err)
+	r.Equal(http.MethodPost, req.Method)
+	r.Equal("http://localhost/api?a=1", req.URL)
+	r.Equal("application/json", req.Header.Get("Content-Type"))
+	r.Equal(`{"a":1}`, req.Body)
+
+	// The body can still be read.
+	body, err := io.ReadAll(request.Body)
+	r.NoError(err)
+	r.Equal(`{"a":1}`, string(body))
+}
+
+func TestExportSnippet(t *testing.T) {
+	req := &ExportedRequest{
+		Method: http.MethodPost,
+		URL:    "http://localhost/api?a=1",
+		Header: http.Header{
+			"Content-Type":  {"application/json"},
+			"Authorization": {"SLIM-AUTH Key=k"},
+		},
+		Body: `{"s":"it's"}`,
+	}
+
+	t.Run("curl", func(t *testing.T) {
+		res, err := ExportSnippet(ExportFormatCurl, req)
+		require.NoError(t, err)
+		require.Equal(t, `curl -X POST 'http://localhost/api?a=1' \
+  -H 'Authorization: SLIM-AUTH Key=k' \
+  -H 'Content-Type: application/json' \
+  --data-raw '{"s":"it'\''s"}'`, res)
+	})
+
+	t.Run("httpie", func(t *testing.T) {
+		res, err := ExportSnippet(ExportFormatHttpie, req)
+		require.NoError(t, err)
+		require.Equal(t, `http POST 'http://localhost/api?a=1' \
+  'Authorization:SLIM-AUTH Key=k' \
+  'Content-Type:application/json' \
+  --raw '{"s":"it'\''s"}'`, res)
+	})
+
+	t.Run("go", func(t *testing.T) {
+		r := require.New(t)
+		goReq := *req
+		goReq.Body = "{\n    \"a\": `b`\n}"
+		goReq.GoImports = []string{"time", "example.com/sign"}
+		goReq.GoSetup = "sign.Sign(req, time.Now())\n\n// done\n"
+
+		res, err := ExportSnippet(ExportFormatGo, &goReq)
+		r.NoError(err)
+
+		// The snippet is valid and already formatted.
+		formatted, err := format.Source([]byte(res))
+		r.NoError(err)
+		r.Equal(string(formatted), res)
+
+		r.Contains(res, "\t\"time\"\n\n\t\"example.com/sign\"\n")
+		r.Contains(res, `body := strings.NewReader("{\n    \"a\": `+"`b`"+`\n}")`)
+		r.Contains(res, `req.Header.Add("Authorization", "SLIM-AUTH Key=k")`)
+		r.Contains(res, "\tsign.Sign(req, time.Now())\n\n\t// done\n")
+	})
+
+	t.Run("go-no-body", func(t *testing.T) {
+		r := require.New(t)
+		res, err := ExportSnippet(ExportFormatGo, &ExportedRequest{Method: http.MethodGet, URL: "http://localhost/"})
+		r.NoError(err)
+		r.Contains(res, `http.NewRequest("GET", "http://localhost/", nil)`)
+		r.NotContains(res, "strings")
+
+		_, err = format.Source([]byte(res))
+		r.NoError(err)
+	})
+
+	t.Run("multipart", func(t *testing.T) {
+		r := require.New(t)
+		formReq := &ExportedRequest{
+			Method: http.MethodPost,
+			URL:    "http://localhost/upload",
+			Header: http.Header{"Content-Type": {"multipart/form-data; boundary=x"}, "X-A": {"1"}},
+			Form:   []KeyValue{{Name: "a", Value: "@1"}, {Name: "f", Value: "/tmp/a b.txt", File: true}},
+		}
+
+		res, err := ExportSnippet(ExportFormatCurl, formReq)
+		r.NoError(err)
+		r.Equal(`curl -X POST 'http://localhost/upload' \
+  -H 'X-A: 1' \
+  --form-string 'a=@1' \
+  -F 'f=@/tmp/a b.txt'`, res)
+
+		res, err = ExportSnippet(ExportFormatHttpie, formReq)
+		r.NoError(err)
+		r.Equal(`http --multipart POST 'http://localhost/upload' \
+  'X-A:1' \
+  'a=@1' \
+  'f@/tmp/a b.txt'`, res)
+
+		res, err = ExportSnippet(ExportFormatGo, formReq)
+		r.NoError(err)
+		formatted, err := format.Source([]byte(res))
+		r.NoError(err)
+		r.Equal(string(formatted), res)
+		r.Contains(res, `form.WriteField("a", "@1")`)
+		r.Contains(res, `addFile("f", "/tmp/a b.txt")`)
+		r.Contains(res, `req.Header.Set("Content-Type", form.FormDataContentType())`)
+		r.NotContains(res, "boundary=x")
+	})
+
+	t.Run("unsupported", func(t *testing.T) {
+		_, err := ExportSnippet("xml", req)
+		require.EqualError(t, err, `unsupported export format "xml"`)
+	})
+}
+
+func TestGoStringLiteral(t *testing.T) {
+	r := require.New(t)
+	r.Equal("`a\nb`", goStringLiteral("a\nb"))
+	r.Equal(`"a\r\nb"`, goStringLiteral("a\r\nb"))
+	r.Equal("\"`\"", goStringLiteral("`"))
+}
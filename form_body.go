@@ -0,0 +1,166 @@
+package client
+
+import (
+	"bytes"
+	"fmt"
+	"mime/multipart"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// 将 query 按顺序追加到地址 rawURL 的 query string 之后。 rawURL 上已有的 query string 保持原样，
+// 如 ?MethodName 形式，若经过 [url.Values.Encode] 会被重新排序并变为 ?MethodName= 。
+func AppendQuery(rawURL string, query []KeyValue) (string, error) {
+	if len(query) == 0 {
+		return rawURL, nil
+	}
+
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", err
+	}
+
+	encoded, err := EncodeForm(query)
+	if err != nil {
+		return "", err
+	}
+
+	if u.RawQuery == "" {
+		u.RawQuery = encoded
+	} else {
+		u.RawQuery += "&" + encoded
+	}
+	return u.String(), nil
+}
+
+// 去掉地址 rawURL 的 query string 中名为 names 之一的参数，其余参数保持原样，见 [AppendQuery] 。
+func RemoveQueryParams(rawURL string, names ...string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.RawQuery == "" {
+		return rawURL
+	}
+
+	parts := strings.Split(u.RawQuery, "&")
+	kept := make([]string, 0, len(parts))
+	for _, part := range parts {
+		key, _, _ := strings.Cut(part, "=")
+		if k, err := url.QueryUnescape(key); err == nil && containsString(names, k) {
+			continue
+		}
+		kept = append(kept, part)
+	}
+
+	if len(kept) == len(parts) {
+		return rawURL
+	}
+	u.RawQuery = strings.Join(kept, "&")
+	return u.String()
+}
+
+func containsString(a []string, s string) bool {
+	for _, v := range a {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// 将字段按顺序编码为 application/x-www-form-urlencoded 格式。 fields 中不能包含文件字段。
+func EncodeForm(fields []KeyValue) (string, error) {
+	var b strings.Builder
+	for i, kv := range fields {
+		if kv.File {
+			return "", fmt.Errorf("the file field %q requires a multipart body", kv.Name)
+		}
+
+		if i > 0 {
+			b.WriteByte('&')
+		}
+		b.WriteString(url.QueryEscape(kv.Name))
+		b.WriteByte('=')
+		b.WriteString(url.QueryEscape(kv.Value))
+	}
+	return b.String(), nil
+}
+
+// 解析 application/x-www-form-urlencoded 格式（也是 query string 的格式）的字段，如 a=1&b=2 。
+// 与 [url.ParseQuery] 不同，保留字段的顺序。
+func ParseFormFields(s string) ([]KeyValue, error) {
+	var res []KeyValue
+	for _, pair := range strings.Split(s, "&") {
+		if pair == "" {
+			continue
+		}
+
+		name, value, _ := strings.Cut(pair, "=")
+		name, err := url.QueryUnescape(name)
+		if err != nil {
+			return nil, err
+		}
+
+		value, err = url.QueryUnescape(value)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, KeyValue{Name: name, Value: value})
+	}
+	return res, nil
+}
+
+// 将字段编码为 multipart/form-data 格式，返回 body 及对应的 Content-Type （含 boundary ）。
+// 文件字段读取 [KeyValue.Value] 给定路径的文件，以文件名作为 filename 。
+func EncodeMultipart(fields []KeyValue) ([]byte, string, error) {
+	buf := new(bytes.Buffer)
+	w := multipart.NewWriter(buf)
+
+	for _, kv := range fields {
+		if !kv.File {
+			if err := w.WriteField(kv.Name, kv.Value); err != nil {
+				return nil, "", err
+			}
+			continue
+		}
+
+		content, err := os.ReadFile(kv.Value)
+		if err != nil {
+			return nil, "", fmt.Errorf("read file of field %q: %w", kv.Name, err)
+		}
+
+		part, err := w.CreateFormFile(kv.Name, filepath.Base(kv.Value))
+		if err != nil {
+			return nil, "", err
+		}
+
+		if _, err := part.Write(content); err != nil {
+			return nil, "", err
+		}
+	}
+
+	if err := w.Close(); err != nil {
+		return nil, "", err
+	}
+	return buf.Bytes(), w.FormDataContentType(), nil
+}
+
+// 返回字段的文本描述，形如 a=1&b=2 ，文件字段的值为 @ 加文件的路径，与 curl 的 -F 参数一致。
+// 用于 [HistoryEntry.Param] 等只需展示的场合。
+func FormatFormFields(fields []KeyValue) string {
+	var b bytes.Buffer
+	for i, kv := range fields {
+		value := kv.Value
+		if kv.File {
+			value = "@" + value
+		}
+
+		if i > 0 {
+			b.WriteByte('&')
+		}
+		b.WriteString(url.QueryEscape(kv.Name))
+		b.WriteByte('=')
+		b.WriteString(url.QueryEscape(value))
+	}
+	return b.String()
+}
@@ -0,0 +1,108 @@
+package client
+
+import (
+	"bytes"
+	"io"
+	"mime"
+	"mime/multipart"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncodeForm(t *testing.T) {
+	r := require.New(t)
+
+	form, err := EncodeForm([]KeyValue{{Name: "b", Value: "1 2"}, {Name: "a", Value: "&"}})
+	r.NoError(err)
+	r.Equal("b=1+2&a=%26", form)
+
+	_, err = EncodeForm([]KeyValue{{Name: "f", Value: "x", File: true}})
+	r.EqualError(err, `the file field "f" requires a multipart body`)
+}
+
+func TestAppendQuery(t *testing.T) {
+	r := require.New(t)
+
+	u, err := AppendQuery("http://temp.org/?flag&b=2#top", []KeyValue{{Name: "a", Value: "1 2"}})
+	r.NoError(err)
+	r.Equal("http://temp.org/?flag&b=2&a=1+2#top", u)
+
+	u, err = AppendQuery("http://temp.org/", []KeyValue{{Name: "b", Value: "2"}, {Name: "a", Value: "1"}})
+	r.NoError(err)
+	r.Equal("http://temp.org/?b=2&a=1", u)
+
+	u, err = AppendQuery("http://temp.org/?flag", nil)
+	r.NoError(err)
+	r.Equal("http://temp.org/?flag", u)
+
+	_, err = AppendQuery("http://temp.org/", []KeyValue{{Name: "f", File: true}})
+	r.Error(err)
+}
+
+func TestRemoveQueryParams(t *testing.T) {
+	r := require.New(t)
+	r.Equal("http://temp.org/?Method&b=2&a=1#top", RemoveQueryParams("http://temp.org/?Method&~a=x&b=2&%7Eb&a=1#top", "~a", "~b"))
+	r.Equal("http://temp.org/?b=2&a=1", RemoveQueryParams("http://temp.org/?b=2&a=1", "~a"))
+	r.Equal("http://temp.org/", RemoveQueryParams("http://temp.org/?~a=1", "~a"))
+	r.Equal("http://temp.org/", RemoveQueryParams("http://temp.org/", "~a"))
+}
+
+func TestParseFormFields(t *testing.T) {
+	r := require.New(t)
+
+	fields, err := ParseFormFields("b=1+2&a=%26&b=&c")
+	r.NoError(err)
+	r.Equal([]KeyValue{{Name: "b", Value: "1 2"}, {Name: "a", Value: "&"}, {Name: "b"}, {Name: "c"}}, fields)
+
+	fields, err = ParseFormFields("")
+	r.NoError(err)
+	r.Nil(fields)
+
+	_, err = ParseFormFields("a=%zz")
+	r.Error(err)
+}
+
+func TestEncodeMultipart(t *testing.T) {
+	r := require.New(t)
+
+	file := filepath.Join(t.TempDir(), "a.txt")
+	r.NoError(os.WriteFile(file, []byte("file content"), 0600))
+
+	body, contentType, err := EncodeMultipart([]KeyValue{
+		{Name: "s", Value: "v"},
+		{Name: "f", Value: file, File: true},
+	})
+	r.NoError(err)
+
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	r.NoError(err)
+	r.Equal("multipart/form-data", mediaType)
+
+	reader := multipart.NewReader(bytes.NewReader(body), params["boundary"])
+	form, err := reader.ReadForm(1 << 20)
+	r.NoError(err)
+	r.Equal([]string{"v"}, form.Value["s"])
+	r.Len(form.File["f"], 1)
+	r.Equal("a.txt", form.File["f"][0].Filename)
+
+	f, err := form.File["f"][0].Open()
+	r.NoError(err)
+	content, _ := io.ReadAll(f)
+	f.Close()
+	r.Equal("file content", string(content))
+
+	_, _, err = EncodeMultipart([]KeyValue{{Name: "f", Value: filepath.Join(t.TempDir(), "none"), File: true}})
+	r.Error(err)
+}
+
+func TestFormatFormFields(t *testing.T) {
+	r := require.New(t)
+	r.Equal("", FormatFormFields(nil))
+	r.Equal("b=1+2&f=%40%2Ftmp%2Fa.txt", FormatFormFields([]KeyValue{
+		{Name: "b", Value: "1 2"},
+		{Name: "f", Value: "/tmp/a.txt", File: true},
+	}))
+}
@@ -8,6 +8,7 @@ require (
 	github.com/cmstar/go-httplib v0.2.0
 	github.com/cmstar/go-logx v1.3.0
 	github.com/cmstar/go-webapi v0.6.12
+	github.com/fsnotify/fsnotify v1.5.4
 	github.com/stretchr/testify v1.8.0
 )
 
@@ -17,7 +18,6 @@ require (
 	github.com/cmstar/go-conv v0.5.0 // indirect
 	github.com/davecgh/go-spew v1.1.1 // indirect
 	github.com/fredbi/uri v0.1.0 // indirect
-	github.com/fsnotify/fsnotify v1.5.4 // indirect
 	github.com/fyne-io/gl-js v0.0.0-20220119005834-d2da28d9ccfe // indirect
 	github.com/fyne-io/glfw-js v0.0.0-20220120001248-ee7290d23504 // indirect
 	github.com/fyne-io/image v0.0.0-20220602074514-4956b0afb3d2 // indirect
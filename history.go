@@ -0,0 +1,175 @@
+package client
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"os"
+	"path"
+	"time"
+)
+
+// 可选接口。 [Client] 实现此接口后，每次在界面上执行完请求，都会通过 handler 报告请求的历史记录。
+type HistoryReporter interface {
+	// 设置接收历史记录的回调。回调可能在非 UI 线程上被调用。
+	SetHistoryHandler(handler func(entry HistoryEntry))
+}
+
+// 可选接口。 [Client] 实现此接口后，可以从外部触发界面上的提交操作，用于重放历史记录。
+type Submitter interface {
+	// 使用当前界面上的值发起请求，效果同点击提交按钮。
+	Submit()
+}
+
+// 每个 Client 最多保留的历史记录条数，超出时丢弃最早的记录。
+const MaxHistoryEntries = 100
+
+// 存储历史记录的文件的名称，每个 Client 一个，位于 Client 的配置目录下，每行一条 JSON 。
+const historyFileName = ".history"
+
+// 一条请求的历史记录。
+type HistoryEntry struct {
+	Time       time.Time      // 发起请求的时间。
+	URL        string         // 实际请求的地址，其中的变量已被替换。
+	Param      string         // 实际请求的参数，其中的变量已被替换。
+	StatusCode int            // 响应的状态码，请求失败时为 0 。
+	Duration   time.Duration  // 请求的耗时。
+	Response   string         // 格式化后的响应 body 。
+	Error      string         // 请求失败时的错误信息。
+	Config     map[string]any // 发起请求时 [Client.GetConfig] 的值，用于重放或另存为配置。
+}
+
+// 根据请求的执行结果创建 [HistoryEntry] 。 response 和 err 为执行请求的返回值。
+func NewHistoryEntry(config map[string]any, url, param string, start time.Time, response *Response, err error) HistoryEntry {
+	entry := HistoryEntry{
+		Time:     start,
+		URL:      url,
+		Param:    param,
+		Duration: time.Since(start),
+		Config:   config,
+	}
+
+	if err != nil {
+		entry.Error = err.Error()
+	}
+
+	if response != nil {
+		entry.StatusCode = response.StatusCode
+		entry.Duration = response.Duration
+		entry.Response = response.FormatBody()
+	}
+
+	return entry
+}
+
+// 读取一个 clientName 下的历史记录，最新的记录在前。
+// 没有历史记录时，返回 nil ；无法解析的行会被忽略。
+func (x *ConfigManager) LoadHistory(clientName string) ([]HistoryEntry, error) {
+	entries, err := x.readHistory(clientName)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, j := 0, len(entries)-1; i < j; i, j = i+1, j-1 {
+		entries[i], entries[j] = entries[j], entries[i]
+	}
+	return entries, nil
+}
+
+// 追加一条历史记录。超出 [MaxHistoryEntries] 时，最早的记录被丢弃。
+// 敏感字段（见 [ConfigManager.SetSecretKeys] ）不被保存，而是替换为 [RedactedText] ，见 [RestoreRedacted] 。
+func (x *ConfigManager) AppendHistory(clientName string, entry HistoryEntry) error {
+	x.secrets.mu.RLock()
+	keys := x.secrets.keys[clientName]
+	x.secrets.mu.RUnlock()
+	entry = redactHistoryEntry(entry, keys)
+
+	entries, err := x.readHistory(clientName)
+	if err != nil {
+		return err
+	}
+
+	entries = append(entries, entry)
+	if len(entries) > MaxHistoryEntries {
+		entries = entries[len(entries)-MaxHistoryEntries:]
+	}
+
+	return x.writeHistory(clientName, entries)
+}
+
+// 清除一个 clientName 下的所有历史记录。
+func (x *ConfigManager) ClearHistory(clientName string) error {
+	p, err := x.getHistoryFilePath(clientName)
+	if err != nil {
+		return x.newError("clear-history", clientName, err)
+	}
+
+	err = os.Remove(p)
+	if err != nil && !os.IsNotExist(err) {
+		return x.newError("clear-history", clientName, err)
+	}
+	return nil
+}
+
+// 按写入顺序读取历史记录。
+func (x *ConfigManager) readHistory(clientName string) ([]HistoryEntry, error) {
+	p, err := x.getHistoryFilePath(clientName)
+	if err != nil {
+		return nil, x.newError("load-history", clientName, err)
+	}
+
+	content, err := os.ReadFile(p)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, x.newError("load-history", clientName, err)
+	}
+
+	var entries []HistoryEntry
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	scanner.Buffer(nil, len(content)+1)
+	for scanner.Scan() {
+		var entry HistoryEntry
+		if json.Unmarshal(scanner.Bytes(), &entry) == nil {
+			entries = append(entries, entry)
+		}
+	}
+
+	return entries, nil
+}
+
+func (x *ConfigManager) writeHistory(clientName string, entries []HistoryEntry) error {
+	p, err := x.getHistoryFilePath(clientName)
+	if err != nil {
+		return x.newError("save-history", clientName, err)
+	}
+
+	err = os.MkdirAll(path.Dir(p), 0755)
+	if err != nil && !os.IsExist(err) {
+		return x.newError("save-history", clientName, err)
+	}
+
+	buf := new(bytes.Buffer)
+	enc := json.NewEncoder(buf)
+	for _, entry := range entries {
+		err = enc.Encode(entry)
+		if err != nil {
+			return x.newError("save-history", clientName, err)
+		}
+	}
+
+	err = os.WriteFile(p, buf.Bytes(), 0600)
+	if err != nil {
+		return x.newError("save-history", clientName, err)
+	}
+	return nil
+}
+
+func (x *ConfigManager) getHistoryFilePath(clientName string) (string, error) {
+	dir, err := x.getClientDirPath(clientName)
+	if err != nil {
+		return "", err
+	}
+	return path.Join(dir, historyFileName), nil
+}
@@ -0,0 +1,73 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigManager_History(t *testing.T) {
+	clearAllConfig()
+	m := NewConfigManager(_CONFIG_PATH)
+	r := require.New(t)
+
+	entries, err := m.LoadHistory("x")
+	r.NoError(err)
+	r.Empty(entries)
+
+	now := time.Now().Round(0)
+	for i := 0; i < MaxHistoryEntries+5; i++ {
+		r.NoError(m.AppendHistory("x", HistoryEntry{
+			Time:   now.Add(time.Duration(i) * time.Second),
+			URL:    fmt.Sprint("http://temp.org/", i),
+			Config: map[string]any{"i": float64(i)},
+		}))
+	}
+
+	entries, err = m.LoadHistory("x")
+	r.NoError(err)
+	r.Len(entries, MaxHistoryEntries)
+
+	// 最新的在前，最早的 5 条被丢弃。
+	last := MaxHistoryEntries + 4
+	r.Equal(fmt.Sprint("http://temp.org/", last), entries[0].URL)
+	r.True(now.Add(time.Duration(last) * time.Second).Equal(entries[0].Time))
+	r.Equal(map[string]any{"i": float64(last)}, entries[0].Config)
+	r.Equal("http://temp.org/5", entries[len(entries)-1].URL)
+
+	// 历史记录文件不应被当作配置列出。
+	keys, err := m.ListKeys("x")
+	r.NoError(err)
+	r.Empty(keys)
+
+	r.NoError(m.ClearHistory("x"))
+	r.NoError(m.ClearHistory("not-exist"))
+	entries, err = m.LoadHistory("x")
+	r.NoError(err)
+	r.Empty(entries)
+}
+
+func TestNewHistoryEntry(t *testing.T) {
+	r := require.New(t)
+	start := time.Now()
+	config := map[string]any{"a": "1"}
+
+	entry := NewHistoryEntry(config, "http://temp.org", "{}", start, &Response{
+		StatusCode: 200,
+		Body:       []byte(`{"a":1}`),
+		Duration:   time.Second,
+	}, nil)
+	r.Equal(200, entry.StatusCode)
+	r.Equal(time.Second, entry.Duration)
+	r.Equal("{\n    \"a\": 1\n}", entry.Response)
+	r.Empty(entry.Error)
+	r.Equal(config, entry.Config)
+
+	entry = NewHistoryEntry(config, "http://temp.org", "{}", start, nil, errors.New("boom"))
+	r.Equal(0, entry.StatusCode)
+	r.Equal("boom", entry.Error)
+	r.Empty(entry.Response)
+}
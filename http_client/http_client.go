@@ -0,0 +1,417 @@
+package http_client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/data/binding"
+	"fyne.io/fyne/v2/widget"
+	"github.com/cmstar/go-errx"
+	client "github.com/cmstar/go-webapi-client"
+)
+
+const (
+	_METHOD    = "Method"
+	_URI       = "Uri"
+	_HEADER    = "Header"
+	_QUERY     = "Query"
+	_BODY_TYPE = "BodyType"
+	_BODY      = "Body"
+	_FORM      = "Form"
+	_TRANSPORT = client.TransportConfigKey
+)
+
+// 可选的 HTTP 方法。
+var httpMethods = []string{
+	http.MethodGet,
+	http.MethodPost,
+	http.MethodPut,
+	http.MethodPatch,
+	http.MethodDelete,
+	http.MethodHead,
+	http.MethodOptions,
+}
+
+// 不属于 go-webapi 协议的普通 HTTP 接口，可自由设置方法、请求头、 query string 和 body 。
+type HttpClient struct {
+	method    binding.String
+	uri       binding.String
+	bodyType  binding.String
+	body      binding.String
+	header    *client.KeyValueTable
+	query     *client.KeyValueTable
+	form      *client.KeyValueTable
+	result    *client.ResponseView
+	transport *client.TransportSettings
+	runner    *client.RequestRunner
+	box       fyne.CanvasObject // 见 Box 。
+
+	mu             sync.Mutex                // 保护 vars 和 historyHandler 。
+	vars           map[string]string         // 当前环境的变量，见 [client.VariableSetter] 。
+	historyHandler func(client.HistoryEntry) // 见 [client.HistoryReporter] 。
+}
+
+var (
+	_ client.Client          = (*HttpClient)(nil)
+	_ client.Executor        = (*HttpClient)(nil)
+	_ client.VariableSetter  = (*HttpClient)(nil)
+	_ client.HistoryReporter = (*HttpClient)(nil)
+	_ client.Submitter       = (*HttpClient)(nil)
+	_ client.Exporter        = (*HttpClient)(nil)
+	_ client.Importer        = (*HttpClient)(nil)
+	_ client.ChangeNotifier  = (*HttpClient)(nil)
+)
+
+// 创建一个 [*HttpClient] 。
+func NewClient() *HttpClient {
+	x := &HttpClient{
+		method:    binding.NewString(),
+		uri:       binding.NewString(),
+		bodyType:  binding.NewString(),
+		body:      binding.NewString(),
+		header:    client.NewKeyValueTable(false),
+		query:     client.NewKeyValueTable(false),
+		form:      client.NewKeyValueTable(true),
+		result:    client.NewResponseView(),
+		transport: client.NewTransportSettings(),
+		runner:    client.NewRequestRunner(),
+	}
+	x.method.Set(http.MethodGet)
+	x.bodyType.Set(BodyNone)
+	return x
+}
+
+func (x *HttpClient) Name() string {
+	return "Http"
+}
+
+func (x *HttpClient) Title() string {
+	return "HTTP"
+}
+
+func (x *HttpClient) GetConfig() map[string]any {
+	method, _ := x.method.Get()
+	uri, _ := x.uri.Get()
+	bodyType, _ := x.bodyType.Get()
+	body, _ := x.body.Get()
+
+	return map[string]any{
+		_METHOD:    method,
+		_URI:       uri,
+		_HEADER:    x.header.GetConfig(),
+		_QUERY:     x.query.GetConfig(),
+		_BODY_TYPE: bodyType,
+		_BODY:      body,
+		_FORM:      x.form.GetConfig(),
+		_TRANSPORT: x.transport.GetConfig(),
+	}
+}
+
+func (x *HttpClient) SetConfig(config map[string]any) {
+	read := func(name string) string {
+		v, ok := config[name]
+		if !ok {
+			x.result.SetText("missing config key: " + name)
+			return ""
+		}
+
+		s, ok := v.(string)
+		if !ok {
+			x.result.SetText("config value error, key: " + name)
+			return ""
+		}
+
+		return s
+	}
+
+	x.method.Set(read(_METHOD))
+	x.uri.Set(read(_URI))
+	x.bodyType.Set(read(_BODY_TYPE))
+	x.body.Set(read(_BODY))
+
+	// 表格为空时保存的是空数组，缺失时同样视为空。
+	x.header.SetConfig(config[_HEADER])
+	x.query.SetConfig(config[_QUERY])
+	x.form.SetConfig(config[_FORM])
+	x.transport.SetConfig(config[_TRANSPORT])
+}
+
+// 返回界面。界面只创建一次，其中的绑定和监听器也只注册一次，每次切换到此 Client 时不会累积。
+func (x *HttpClient) Box() fyne.CanvasObject {
+	if x.box == nil {
+		x.box = x.makeBox()
+	}
+	return x.box
+}
+
+func (x *HttpClient) makeBox() fyne.CanvasObject {
+	bodyInput := widget.NewMultiLineEntry()
+	bodyInput.Bind(x.body)
+	bodyInput.SetMinRowsVisible(8)
+
+	// 根据 body 的类型展示文本框或表单字段的表格。
+	formBox := x.form.Box()
+	bodyBox := container.NewVBox(bodyInput, formBox)
+	x.bodyType.AddListener(binding.NewDataListener(func() {
+		bodyType, _ := x.bodyType.Get()
+		switch bodyType {
+		case BodyRaw, BodyJson:
+			bodyInput.Show()
+			formBox.Hide()
+		case BodyForm, BodyMultipart:
+			bodyInput.Hide()
+			formBox.Show()
+		default:
+			bodyInput.Hide()
+			formBox.Hide()
+		}
+	}))
+
+	requestForm := &widget.Form{
+		Items: []*widget.FormItem{
+			{Text: "Method", Widget: client.NewBoundSelect(httpMethods, x.method)},
+			{Text: "URL", Widget: widget.NewEntryWithData(x.uri)},
+			{Text: "Query", Widget: x.query.Box()},
+			{Text: "Headers", Widget: x.header.Box()},
+			{Text: "Body", Widget: client.NewBoundSelect([]string{BodyNone, BodyRaw, BodyJson, BodyForm, BodyMultipart}, x.bodyType)},
+			{Text: "", Widget: bodyBox},
+		},
+	}
+
+	container := container.NewHSplit(
+		container.NewVScroll(container.NewVBox(requestForm, x.runner.Buttons(x.onSubmit), x.transport.Box())),
+		x.result.Box(),
+	)
+
+	return container
+}
+
+func (x *HttpClient) onSubmit() {
+	// 采用异步请求，同一时间只执行一个请求，正在执行时提交操作被忽略。
+	x.runner.Run(func(ctx context.Context) {
+		x.result.SetText("requesting ...")
+
+		// 发现更新 binding.String 速度太快会来不及反馈到界面上。等一下下。
+		<-time.After(200 * time.Millisecond)
+
+		config := x.GetConfig()
+		request, err := x.Request()
+		if err != nil {
+			x.result.SetText(err.Error())
+			return
+		}
+
+		start := time.Now()
+		response, err := x.performRequest(ctx, request)
+
+		switch {
+		case err != nil && ctx.Err() != nil:
+			x.result.SetText(client.CancelledText)
+		case err != nil:
+			x.result.SetText(err.Error())
+		default:
+			x.result.SetResponse(response)
+		}
+
+		x.mu.Lock()
+		handler := x.historyHandler
+		x.mu.Unlock()
+
+		if handler != nil {
+			handler(client.NewHistoryEntry(config, request.URL, request.ParamText(), start, response, err))
+		}
+	})
+}
+
+// 实现 [client.Submitter] 。
+func (x *HttpClient) Submit() {
+	x.onSubmit()
+}
+
+// 实现 [client.ChangeNotifier] 。
+func (x *HttpClient) AddChangeListener(fn func()) {
+	client.AddBindingListener(fn, x.method, x.uri, x.bodyType, x.body)
+	x.header.AddChangeListener(fn)
+	x.query.AddChangeListener(fn)
+	x.form.AddChangeListener(fn)
+	x.transport.AddChangeListener(fn)
+}
+
+// 实现 [client.HistoryReporter] 。
+func (x *HttpClient) SetHistoryHandler(handler func(entry client.HistoryEntry)) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	x.historyHandler = handler
+}
+
+// 使用当前的配置执行请求，用于命令行模式。实现 [client.Executor] 。
+// 响应的状态码不是 2xx 时返回错误。
+func (x *HttpClient) Execute(ctx context.Context) (string, error) {
+	request, err := x.Request()
+	if err != nil {
+		return "", err
+	}
+
+	response, err := request.Execute(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	responseText := response.FormatBody()
+	if !response.IsSuccess() {
+		return "", fmt.Errorf("%s\n%s", response.Status, responseText)
+	}
+	return responseText, nil
+}
+
+// 实现 [client.Exporter] 。普通的 HTTP 请求不需要签名， signed 被忽略。
+func (x *HttpClient) ExportRequest(signed bool) (*client.ExportedRequest, error) {
+	request, err := x.Request()
+	if err != nil {
+		return nil, err
+	}
+
+	// multipart 的 body 含有文件的内容和随机的 boundary ，改为导出各字段，见 [client.ExportedRequest.Form] 。
+	var form []client.KeyValue
+	if request.BodyType == BodyMultipart {
+		form = request.Form
+		request.BodyType = BodyNone
+		request.Form = nil
+	}
+
+	req, err := request.Build(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := client.NewExportedRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	res.Form = form
+	return res, nil
+}
+
+// 实现 [client.Importer] 。任何请求都可以用 HTTP 表示，为避免抢先于具体的协议被选中，总是返回 false ，
+// 需在导入时手动选择。
+func (x *HttpClient) MatchRequest(req *client.CurlRequest) bool {
+	return false
+}
+
+// 实现 [client.Importer] 。 URL 上的 query string 被拆分到 Query 表格中；
+// JSON 和 application/x-www-form-urlencoded 的 body 分别导入为对应的类型，其余按原文导入。
+func (x *HttpClient) ImportRequest(req *client.CurlRequest) (map[string]any, error) {
+	u, err := url.Parse(req.URL)
+	if err != nil {
+		return nil, err
+	}
+
+	query, err := client.ParseFormFields(u.RawQuery)
+	if err != nil {
+		return nil, fmt.Errorf("the query string is not valid: %w", err)
+	}
+	u.RawQuery = ""
+
+	contentType := req.Header.Get("Content-Type")
+	bodyType := BodyNone
+	body := ""
+	var form []client.KeyValue
+	switch {
+	case req.Body == "":
+	case strings.Contains(contentType, "json"):
+		bodyType = BodyJson
+		body = req.Body
+	case strings.Contains(contentType, "x-www-form-urlencoded"):
+		form, err = client.ParseFormFields(req.Body)
+		if err != nil {
+			return nil, fmt.Errorf("the form body is not valid: %w", err)
+		}
+		bodyType = BodyForm
+	default:
+		bodyType = BodyRaw
+		body = req.Body
+	}
+
+	// Content-Type 由 body 的类型决定， Content-Length 由请求自动计算，其余请求头按原样导入。
+	skip := []string{"Content-Length"}
+	if bodyType != BodyRaw {
+		skip = append(skip, "Content-Type")
+	}
+	header := client.HeaderKeyValues(req.Header, skip...)
+
+	return map[string]any{
+		_METHOD:    req.Method,
+		_URI:       u.String(),
+		_HEADER:    client.KeyValuesToConfig(header),
+		_QUERY:     client.KeyValuesToConfig(query),
+		_BODY_TYPE: bodyType,
+		_BODY:      body,
+		_FORM:      client.KeyValuesToConfig(form),
+	}, nil
+}
+
+// 实现 [client.VariableSetter] 。
+func (x *HttpClient) SetVariables(vars map[string]string) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	x.vars = vars
+}
+
+// 根据当前界面上的值创建 [HttpRequest] ，各字段中的变量已被替换。
+// 连接参数有误时返回 error 。
+func (x *HttpClient) Request() (*HttpRequest, error) {
+	x.mu.Lock()
+	vars := x.vars
+	x.mu.Unlock()
+
+	get := func(v binding.String) string {
+		s, _ := v.Get()
+		return client.ExpandVariables(s, vars)
+	}
+
+	expand := func(items []client.KeyValue) []client.KeyValue {
+		for i := range items {
+			items[i].Name = client.ExpandVariables(items[i].Name, vars)
+			items[i].Value = client.ExpandVariables(items[i].Value, vars)
+		}
+		return items
+	}
+
+	// 以下几项来自下拉框，不需要替换变量。
+	method, _ := x.method.Get()
+	bodyType, _ := x.bodyType.Get()
+
+	httpClient, err := x.transport.NewHttpClient()
+	if err != nil {
+		return nil, err
+	}
+
+	return &HttpRequest{
+		Method:   method,
+		URL:      get(x.uri),
+		Header:   expand(x.header.Items()),
+		Query:    expand(x.query.Items()),
+		BodyType: bodyType,
+		Body:     get(x.body),
+		Form:     expand(x.form.Items()),
+
+		HttpClient: httpClient,
+	}, nil
+}
+
+func (x *HttpClient) performRequest(ctx context.Context, request *HttpRequest) (response *client.Response, err error) {
+	defer func() {
+		if err == nil {
+			err = errx.PreserveRecover("", recover())
+		}
+	}()
+
+	return request.Execute(ctx)
+}
@@ -0,0 +1,134 @@
+package http_client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/cmstar/go-httplib/headers"
+	client "github.com/cmstar/go-webapi-client"
+)
+
+// body 的类型。
+const (
+	BodyNone      = "none"      // 没有 body 。
+	BodyRaw       = "raw"       // 原样发送 Body ，默认 Content-Type 为 text/plain 。
+	BodyJson      = "json"      // Body 须是 JSON ， Content-Type 为 application/json 。
+	BodyForm      = "form"      // Form 中的字段以 application/x-www-form-urlencoded 的形式发送。
+	BodyMultipart = "multipart" // Form 中的字段以 multipart/form-data 的形式发送，可以包含文件。
+)
+
+// 描述一个普通的 HTTP 请求，不依赖界面，可直接在代码中使用。
+type HttpRequest struct {
+	Method string            // HTTP 方法。为空时，使用 GET 。
+	URL    string            // 请求的地址，可以带有 query string 。
+	Header []client.KeyValue // 请求头。其中的 Content-Type 优先于由 BodyType 决定的值； Host 用于设置 [http.Request.Host] 。
+	Query  []client.KeyValue // 追加到 URL 上的 query string 参数。
+
+	// body 的类型，为 BodyXxx 之一。为空时，默认为 [BodyNone] 。
+	BodyType string
+
+	// [BodyRaw] 和 [BodyJson] 时的 body 。
+	Body string
+
+	// [BodyForm] 和 [BodyMultipart] 时的字段。 [client.KeyValue.File] 仅在 [BodyMultipart] 时可用。
+	Form []client.KeyValue
+
+	// 执行请求所用的 [http.Client] 。若为 nil ，使用 [http.DefaultClient] 。
+	HttpClient *http.Client
+}
+
+// 执行请求。
+// 仅当请求无法发出或响应无法读取时返回 error ，非 2xx 的响应不被视为错误。
+func (x *HttpRequest) Execute(ctx context.Context) (*client.Response, error) {
+	request, err := x.Build(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return client.SendRequest(x.HttpClient, request)
+}
+
+// 根据给定的参数构建 [http.Request] 。 [BodyMultipart] 时，文件的内容被读入内存。
+func (x *HttpRequest) Build(ctx context.Context) (*http.Request, error) {
+	method := x.Method
+	if method == "" {
+		method = http.MethodGet
+	}
+
+	uri, err := client.AppendQuery(x.URL, x.Query)
+	if err != nil {
+		return nil, err
+	}
+
+	body, contentType, err := x.buildBody()
+	if err != nil {
+		return nil, err
+	}
+
+	request, err := http.NewRequestWithContext(ctx, method, uri, body)
+	if err != nil {
+		return nil, err
+	}
+
+	if contentType != "" {
+		request.Header.Set(headers.ContentType, contentType)
+	}
+
+	client.ApplyHeaders(request, x.Header)
+
+	return request, nil
+}
+
+// 返回 body 及其 Content-Type 。没有 body 时返回 nil 。
+func (x *HttpRequest) buildBody() (io.Reader, string, error) {
+	switch x.BodyType {
+	case "", BodyNone:
+		return nil, "", nil
+
+	case BodyRaw:
+		return strings.NewReader(x.Body), "text/plain; charset=utf-8", nil
+
+	case BodyJson:
+		if !json.Valid([]byte(x.Body)) {
+			return nil, "", fmt.Errorf("the request body is not a valid JSON")
+		}
+		return strings.NewReader(x.Body), "application/json", nil
+
+	case BodyForm:
+		form, err := client.EncodeForm(x.Form)
+		if err != nil {
+			return nil, "", err
+		}
+		return strings.NewReader(form), "application/x-www-form-urlencoded", nil
+
+	case BodyMultipart:
+		body, contentType, err := client.EncodeMultipart(x.Form)
+		if err != nil {
+			return nil, "", err
+		}
+		return bytes.NewReader(body), contentType, nil
+
+	default:
+		return nil, "", fmt.Errorf("unsupported body type %q", x.BodyType)
+	}
+}
+
+// 返回请求参数的描述，用于 [client.HistoryEntry] 。
+// [BodyForm] 和 [BodyMultipart] 时的格式见 [client.FormatFormFields] 。
+func (x *HttpRequest) ParamText() string {
+	switch x.BodyType {
+	case BodyRaw, BodyJson:
+		return x.Body
+
+	case BodyForm, BodyMultipart:
+		return client.FormatFormFields(x.Form)
+
+	default:
+		return ""
+	}
+}
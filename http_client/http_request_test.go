@@ -0,0 +1,263 @@
+package http_client
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	client "github.com/cmstar/go-webapi-client"
+	"github.com/stretchr/testify/require"
+)
+
+// 将收到的请求以 JSON 的形式返回。
+func newTestServer() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		res := map[string]any{
+			"Method":      r.Method,
+			"Query":       r.URL.RawQuery,
+			"ContentType": r.Header.Get("Content-Type"),
+			"Tenant":      r.Header.Values("X-Tenant"),
+			"Host":        r.Host,
+		}
+
+		switch {
+		case r.Header.Get("Content-Type") == "application/x-www-form-urlencoded":
+			r.ParseForm()
+			res["Form"] = r.PostForm
+		case r.MultipartForm == nil && r.ParseMultipartForm(1<<20) == nil:
+			res["Form"] = r.MultipartForm.Value
+			files := make(map[string]string)
+			for name, fs := range r.MultipartForm.File {
+				f, _ := fs[0].Open()
+				content, _ := io.ReadAll(f)
+				f.Close()
+				files[name] = fs[0].Filename + ":" + string(content)
+			}
+			res["Files"] = files
+		default:
+			body, _ := io.ReadAll(r.Body)
+			res["Body"] = string(body)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(res)
+	}))
+}
+
+func TestHttpRequest_Execute(t *testing.T) {
+	ts := newTestServer()
+	defer ts.Close()
+
+	file := filepath.Join(t.TempDir(), "a.txt")
+	require.NoError(t, os.WriteFile(file, []byte("file content"), 0600))
+
+	cases := []struct {
+		name string
+		req  HttpRequest
+		want map[string]any
+	}{
+		{
+			name: "get",
+			req: HttpRequest{
+				URL:    "/?a=1",
+				Query:  []client.KeyValue{{Name: "b", Value: "x y"}},
+				Header: []client.KeyValue{{Name: "x-tenant", Value: "t1"}, {Name: "X-Tenant", Value: "t2"}, {Name: "Host", Value: "example.org"}},
+			},
+			want: map[string]any{"Method": "GET", "Query": "a=1&b=x+y", "Tenant": []any{"t1", "t2"}, "Host": "example.org", "Body": ""},
+		},
+		{
+			name: "query-order",
+			req: HttpRequest{
+				URL:   "/?z&y=1",
+				Query: []client.KeyValue{{Name: "b", Value: "2"}, {Name: "a", Value: "1"}},
+			},
+			want: map[string]any{"Method": "GET", "Query": "z&y=1&b=2&a=1"},
+		},
+		{
+			name: "raw",
+			req: HttpRequest{
+				Method: http.MethodPut, URL: "/", BodyType: BodyRaw, Body: "<a/>",
+				Header: []client.KeyValue{{Name: "Content-Type", Value: "text/xml"}},
+			},
+			want: map[string]any{"Method": "PUT", "ContentType": "text/xml", "Body": "<a/>"},
+		},
+		{
+			name: "json",
+			req:  HttpRequest{Method: http.MethodPost, URL: "/", BodyType: BodyJson, Body: `{"a":1}`},
+			want: map[string]any{"Method": "POST", "ContentType": "application/json", "Body": `{"a":1}`},
+		},
+		{
+			name: "form",
+			req: HttpRequest{
+				Method: http.MethodPost, URL: "/", BodyType: BodyForm,
+				Form: []client.KeyValue{{Name: "a", Value: "1"}, {Name: "a", Value: "2"}},
+			},
+			want: map[string]any{"Method": "POST", "ContentType": "application/x-www-form-urlencoded", "Form": map[string]any{"a": []any{"1", "2"}}},
+		},
+		{
+			name: "multipart",
+			req: HttpRequest{
+				Method: http.MethodPost, URL: "/", BodyType: BodyMultipart,
+				Form: []client.KeyValue{{Name: "a", Value: "1"}, {Name: "f", Value: file, File: true}},
+			},
+			want: map[string]any{"Method": "POST", "Form": map[string]any{"a": []any{"1"}}, "Files": map[string]any{"f": "a.txt:file content"}},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			r := require.New(t)
+			c.req.URL = ts.URL + c.req.URL
+			res, err := c.req.Execute(context.Background())
+			r.NoError(err)
+			r.Equal(200, res.StatusCode)
+
+			var got map[string]any
+			r.NoError(json.Unmarshal(res.Body, &got))
+			for k, v := range c.want {
+				r.Equal(v, got[k], k)
+			}
+		})
+	}
+}
+
+func TestHttpRequest_Build(t *testing.T) {
+	r := require.New(t)
+
+	_, err := (&HttpRequest{URL: "http://temp.org/", BodyType: BodyJson, Body: "{"}).Build(context.Background())
+	r.EqualError(err, "the request body is not a valid JSON")
+
+	_, err = (&HttpRequest{URL: "http://temp.org/", BodyType: BodyForm, Form: []client.KeyValue{{Name: "f", File: true}}}).Build(context.Background())
+	r.EqualError(err, `the file field "f" requires a multipart body`)
+
+	_, err = (&HttpRequest{URL: "http://temp.org/", BodyType: "xml"}).Build(context.Background())
+	r.EqualError(err, `unsupported body type "xml"`)
+
+	req := HttpRequest{BodyType: BodyMultipart, Form: []client.KeyValue{{Name: "a", Value: "1"}, {Name: "f", Value: "/tmp/a b", File: true}}}
+	r.Equal("a=1&f=%40%2Ftmp%2Fa+b", req.ParamText())
+}
+
+func TestHttpClient_Config(t *testing.T) {
+	r := require.New(t)
+	c := NewClient()
+
+	conf := map[string]any{
+		_METHOD:    http.MethodPost,
+		_URI:       "http://localhost/{{path}}",
+		_HEADER:    []any{map[string]any{"Name": "X-Tenant", "Value": "{{tenant}}"}},
+		_QUERY:     []any{},
+		_BODY_TYPE: BodyMultipart,
+		_BODY:      "",
+		_FORM:      []any{map[string]any{"Name": "f", "Value": "/tmp/a.txt", "File": true}},
+	}
+	c.SetConfig(conf)
+
+	got := c.GetConfig()
+	r.NotNil(got[_TRANSPORT])
+	delete(got, _TRANSPORT)
+	r.Equal(conf, got)
+
+	c.SetVariables(map[string]string{"path": "p", "tenant": "t1"})
+	req, err := c.Request()
+	r.NoError(err)
+	r.Equal("http://localhost/p", req.URL)
+	r.Equal([]client.KeyValue{{Name: "X-Tenant", Value: "t1"}}, req.Header)
+	r.Equal([]client.KeyValue{{Name: "f", Value: "/tmp/a.txt", File: true}}, req.Form)
+}
+
+func TestHttpClient_AddChangeListener(t *testing.T) {
+	r := require.New(t)
+	c := NewClient()
+
+	var changed atomic.Int32
+	c.AddChangeListener(func() { changed.Add(1) })
+
+	// binding 的回调是异步的，添加时也会回调一次，等它们都完成。
+	time.Sleep(100 * time.Millisecond)
+	changed.Store(0)
+
+	c.query.SetItems([]client.KeyValue{{Name: "a"}})
+	r.Equal(int32(1), changed.Load())
+
+	c.uri.Set("http://localhost/changed")
+	r.Eventually(func() bool { return changed.Load() == 2 }, time.Second, 10*time.Millisecond)
+}
+
+func TestHttpClient_ExportRequest(t *testing.T) {
+	r := require.New(t)
+	c := NewClient()
+	c.SetConfig(map[string]any{
+		_METHOD:    http.MethodPost,
+		_URI:       "http://localhost/?flag",
+		_BODY_TYPE: BodyMultipart,
+		_FORM:      []any{map[string]any{"Name": "a", "Value": "1"}, map[string]any{"Name": "f", "Value": "/not/exist.txt", "File": true}},
+	})
+
+	// 文件不会被读取。
+	req, err := c.ExportRequest(true)
+	r.NoError(err)
+	r.Equal("http://localhost/?flag", req.URL)
+	r.Empty(req.Body)
+	r.Empty(req.Header.Get("Content-Type"))
+	r.Equal([]client.KeyValue{{Name: "a", Value: "1"}, {Name: "f", Value: "/not/exist.txt", File: true}}, req.Form)
+}
+
+func TestHttpClient_ImportRequest(t *testing.T) {
+	c := NewClient()
+
+	t.Run("form", func(t *testing.T) {
+		r := require.New(t)
+		req, err := client.ParseCurl(`curl 'http://localhost/api?b=2&a=1' -H 'X-Tenant: t1' -d 'y=1&x=2'`)
+		r.NoError(err)
+		r.False(c.MatchRequest(req))
+
+		conf, err := c.ImportRequest(req)
+		r.NoError(err)
+		r.Equal(map[string]any{
+			_METHOD: http.MethodPost,
+			_URI:    "http://localhost/api",
+			_HEADER: []any{map[string]any{"Name": "X-Tenant", "Value": "t1"}},
+			_QUERY: []any{
+				map[string]any{"Name": "b", "Value": "2"},
+				map[string]any{"Name": "a", "Value": "1"},
+			},
+			_BODY_TYPE: BodyForm,
+			_BODY:      "",
+			_FORM: []any{
+				map[string]any{"Name": "y", "Value": "1"},
+				map[string]any{"Name": "x", "Value": "2"},
+			},
+		}, conf)
+	})
+
+	t.Run("json", func(t *testing.T) {
+		r := require.New(t)
+		req, err := client.ParseCurl(`curl -X PUT http://localhost/api --json '{"a":1}'`)
+		r.NoError(err)
+
+		conf, err := c.ImportRequest(req)
+		r.NoError(err)
+		r.Equal(http.MethodPut, conf[_METHOD])
+		r.Equal(BodyJson, conf[_BODY_TYPE])
+		r.Equal(`{"a":1}`, conf[_BODY])
+	})
+
+	t.Run("raw", func(t *testing.T) {
+		r := require.New(t)
+		req, err := client.ParseCurl(`curl http://localhost/api -H 'Content-Type: text/xml' -d '<a/>'`)
+		r.NoError(err)
+
+		conf, err := c.ImportRequest(req)
+		r.NoError(err)
+		r.Equal(BodyRaw, conf[_BODY_TYPE])
+		r.Equal("<a/>", conf[_BODY])
+		r.Equal([]any{map[string]any{"Name": "Content-Type", "Value": "text/xml"}}, conf[_HEADER])
+	})
+}
@@ -0,0 +1,287 @@
+package client
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// 一个名值对，如请求头、 query string 参数、表单字段。
+type KeyValue struct {
+	Name  string
+	Value string
+	File  bool // 为 true 时 Value 是本地文件的路径，用于 multipart/form-data 中的文件字段。
+}
+
+// 从配置中读取 [KeyValue] 的列表， v 的格式同 [KeyValuesToConfig] 的返回值。
+// 为 nil 或格式不正确时返回 nil ，其中格式不正确的项被忽略。
+func KeyValuesFromConfig(v any) []KeyValue {
+	list, _ := v.([]any)
+
+	var res []KeyValue
+	for _, item := range list {
+		m, ok := item.(map[string]any)
+		if !ok {
+			continue
+		}
+
+		name, _ := m["Name"].(string)
+		value, _ := m["Value"].(string)
+		file, _ := m["File"].(bool)
+		res = append(res, KeyValue{Name: name, Value: value, File: file})
+	}
+	return res
+}
+
+// 将 [KeyValue] 的列表转换为配置中的格式，每项为一个含有 Name 、 Value 的 map ，
+// 文件字段还带有 File: true 。
+func KeyValuesToConfig(items []KeyValue) []any {
+	res := make([]any, 0, len(items))
+	for _, kv := range items {
+		m := map[string]any{
+			"Name":  kv.Name,
+			"Value": kv.Value,
+		}
+
+		if kv.File {
+			m["File"] = true
+		}
+		res = append(res, m)
+	}
+	return res
+}
+
+// 将 header 设置到请求上。同名的头中，第一个覆盖请求上已有的值，其余的追加；
+// Host 用于设置 [http.Request.Host] 。
+func ApplyHeaders(request *http.Request, header []KeyValue) {
+	seen := make(map[string]bool)
+	for _, kv := range header {
+		if strings.EqualFold(kv.Name, "Host") {
+			request.Host = kv.Value
+			continue
+		}
+
+		name := http.CanonicalHeaderKey(kv.Name)
+		if !seen[name] {
+			request.Header.Del(name)
+			seen[name] = true
+		}
+		request.Header.Add(name, kv.Value)
+	}
+}
+
+// 将 h 转换为 [KeyValue] 的列表，按名称的字典顺序排列，使结果是确定的。
+// 名称在 skip 中的请求头被忽略，比较时不区分大小写。
+func HeaderKeyValues(h http.Header, skip ...string) []KeyValue {
+	names := make([]string, 0, len(h))
+	for name := range h {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var res []KeyValue
+outer:
+	for _, name := range names {
+		for _, s := range skip {
+			if strings.EqualFold(name, s) {
+				continue outer
+			}
+		}
+
+		for _, v := range h[name] {
+			res = append(res, KeyValue{Name: name, Value: v})
+		}
+	}
+	return res
+}
+
+// 编辑一组 [KeyValue] 的界面组件，供各 [Client] 的实现嵌入到自己的界面中，如请求头、 query string 参数。
+// 名称为空的行在 [KeyValueTable.Items] 中被忽略。可在多个 goroutine 中使用。
+type KeyValueTable struct {
+	files bool // 是否可以添加文件字段。
+
+	mu        sync.Mutex
+	items     []KeyValue
+	rows      *fyne.Container // 最近一次 Box() 中的行，尚未展示时为 nil 。
+	listeners []func()        // 见 AddChangeListener 。
+}
+
+// 创建一个 [*KeyValueTable] 。 files 为 true 时，每行可选择为文本或文件，文件可通过对话框选择。
+func NewKeyValueTable(files bool) *KeyValueTable {
+	return &KeyValueTable{files: files}
+}
+
+// 添加各行被修改（包括添加、删除和 SetItems ）后的回调。实现 [ChangeNotifier] 。
+func (x *KeyValueTable) AddChangeListener(fn func()) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	x.listeners = append(x.listeners, fn)
+}
+
+// 返回编辑各行的界面，底部的 ADD 按钮添加一行。
+func (x *KeyValueTable) Box() fyne.CanvasObject {
+	rows := container.NewVBox()
+
+	x.mu.Lock()
+	x.rows = rows
+	x.mu.Unlock()
+	x.refresh()
+
+	btnAdd := widget.NewButtonWithIcon("ADD", theme.ContentAddIcon(), func() {
+		x.mu.Lock()
+		x.items = append(x.items, KeyValue{})
+		x.mu.Unlock()
+		x.refresh()
+		x.changed()
+	})
+
+	return container.NewVBox(rows, container.NewHBox(btnAdd))
+}
+
+// 返回名称不为空的各行。
+func (x *KeyValueTable) Items() []KeyValue {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	res := make([]KeyValue, 0, len(x.items))
+	for _, kv := range x.items {
+		if kv.Name != "" {
+			res = append(res, kv)
+		}
+	}
+	return res
+}
+
+// 设置各行，并刷新界面。
+func (x *KeyValueTable) SetItems(items []KeyValue) {
+	x.mu.Lock()
+	x.items = append([]KeyValue(nil), items...)
+	x.mu.Unlock()
+	x.refresh()
+	x.changed()
+}
+
+// 读取当前界面的配置，格式见 [KeyValuesToConfig] 。
+func (x *KeyValueTable) GetConfig() []any {
+	return KeyValuesToConfig(x.Items())
+}
+
+// 设置当前界面的配置， config 的格式见 [KeyValuesFromConfig] 。为 nil 时清空。
+func (x *KeyValueTable) SetConfig(config any) {
+	x.SetItems(KeyValuesFromConfig(config))
+}
+
+// 根据 items 重新创建界面上的各行。
+func (x *KeyValueTable) refresh() {
+	x.mu.Lock()
+	rows := x.rows
+	items := append([]KeyValue(nil), x.items...)
+	x.mu.Unlock()
+
+	if rows == nil {
+		return
+	}
+
+	objects := make([]fyne.CanvasObject, 0, len(items))
+	for i, kv := range items {
+		objects = append(objects, x.makeRow(i, kv))
+	}
+	rows.Objects = objects
+	rows.Refresh()
+}
+
+// 修改第 i 行，行已被删除时忽略。
+func (x *KeyValueTable) update(i int, fn func(kv *KeyValue)) {
+	x.mu.Lock()
+	if i < len(x.items) {
+		fn(&x.items[i])
+	}
+	x.mu.Unlock()
+	x.changed()
+}
+
+// 调用 AddChangeListener 添加的回调。不能在持有锁时调用。
+func (x *KeyValueTable) changed() {
+	x.mu.Lock()
+	listeners := x.listeners
+	x.mu.Unlock()
+
+	for _, fn := range listeners {
+		fn()
+	}
+}
+
+func (x *KeyValueTable) makeRow(i int, kv KeyValue) fyne.CanvasObject {
+	nameInput := widget.NewEntry()
+	nameInput.SetPlaceHolder("name")
+	nameInput.SetText(kv.Name)
+	nameInput.OnChanged = func(s string) {
+		x.update(i, func(kv *KeyValue) { kv.Name = s })
+	}
+
+	valueInput := widget.NewEntry()
+	valueInput.SetText(kv.Value)
+	valueInput.OnChanged = func(s string) {
+		x.update(i, func(kv *KeyValue) { kv.Value = s })
+	}
+
+	btnDelete := widget.NewButtonWithIcon("", theme.DeleteIcon(), func() {
+		x.mu.Lock()
+		if i < len(x.items) {
+			x.items = append(x.items[:i], x.items[i+1:]...)
+		}
+		x.mu.Unlock()
+		x.refresh()
+		x.changed()
+	})
+
+	if !x.files {
+		valueInput.SetPlaceHolder("value")
+		return container.NewBorder(nil, nil, nil, btnDelete, container.NewGridWithColumns(2, nameInput, valueInput))
+	}
+
+	// 文件字段的值是文件的路径，可手动输入或通过对话框选择。
+	btnBrowse := widget.NewButtonWithIcon("", theme.FolderOpenIcon(), nil)
+	btnBrowse.OnTapped = func() {
+		win := windowFor(btnBrowse)
+		if win == nil {
+			return
+		}
+
+		d := dialog.NewFileOpen(func(r fyne.URIReadCloser, err error) {
+			if err != nil || r == nil {
+				return
+			}
+			r.Close()
+			valueInput.SetText(r.URI().Path())
+		}, win)
+		d.Show()
+	}
+
+	setFile := func(file bool) {
+		if file {
+			valueInput.SetPlaceHolder("file path")
+			btnBrowse.Show()
+		} else {
+			valueInput.SetPlaceHolder("value")
+			btnBrowse.Hide()
+		}
+	}
+	setFile(kv.File)
+
+	fileCheck := widget.NewCheck("File", func(file bool) {
+		x.update(i, func(kv *KeyValue) { kv.File = file })
+		setFile(file)
+	})
+	fileCheck.SetChecked(kv.File)
+
+	return container.NewBorder(nil, nil, fileCheck, container.NewHBox(btnBrowse, btnDelete),
+		container.NewGridWithColumns(2, nameInput, valueInput))
+}
@@ -0,0 +1,77 @@
+package client
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeyValuesConfig(t *testing.T) {
+	r := require.New(t)
+
+	items := []KeyValue{
+		{Name: "a", Value: "1"},
+		{Name: "f", Value: "/tmp/f.txt", File: true},
+	}
+
+	// 经过 JSON 序列化后，与保存到文件再读取的格式一致。
+	content, err := json.Marshal(KeyValuesToConfig(items))
+	r.NoError(err)
+	r.Equal(`[{"Name":"a","Value":"1"},{"File":true,"Name":"f","Value":"/tmp/f.txt"}]`, string(content))
+
+	var v any
+	r.NoError(json.Unmarshal(content, &v))
+	r.Equal(items, KeyValuesFromConfig(v))
+
+	r.Nil(KeyValuesFromConfig(nil))
+	r.Nil(KeyValuesFromConfig("bad"))
+	r.Equal([]KeyValue{{Name: "b"}}, KeyValuesFromConfig([]any{1, map[string]any{"Name": "b", "Value": 2}}))
+}
+
+func TestKeyValueTable(t *testing.T) {
+	r := require.New(t)
+	table := NewKeyValueTable(false)
+	r.Empty(table.Items())
+
+	// 名称为空的行被忽略。
+	table.SetItems([]KeyValue{{Name: "a", Value: "1"}, {Value: "2"}})
+	r.Equal([]KeyValue{{Name: "a", Value: "1"}}, table.Items())
+	r.Equal([]any{map[string]any{"Name": "a", "Value": "1"}}, table.GetConfig())
+
+	changed, changed2 := 0, 0
+	table.AddChangeListener(func() { changed++ })
+	table.AddChangeListener(func() { changed2++ })
+	table.SetConfig(nil)
+	r.Empty(table.Items())
+	r.Equal(1, changed)
+	r.Equal(1, changed2)
+}
+
+func TestApplyHeaders(t *testing.T) {
+	r := require.New(t)
+	request, err := http.NewRequest(http.MethodGet, "http://temp.org/", nil)
+	r.NoError(err)
+	request.Header.Set("Accept", "*/*")
+
+	ApplyHeaders(request, []KeyValue{
+		{Name: "accept", Value: "a"},
+		{Name: "Accept", Value: "b"},
+		{Name: "Host", Value: "example.org"},
+	})
+	r.Equal([]string{"a", "b"}, request.Header.Values("Accept"))
+	r.Equal("example.org", request.Host)
+	r.Empty(request.Header.Get("Host"))
+}
+
+func TestHeaderKeyValues(t *testing.T) {
+	r := require.New(t)
+	h := http.Header{
+		"X-B":            {"2", "3"},
+		"X-A":            {"1"},
+		"Content-Length": {"10"},
+	}
+	r.Equal([]KeyValue{{Name: "X-A", Value: "1"}, {Name: "X-B", Value: "2"}, {Name: "X-B", Value: "3"}}, HeaderKeyValues(h, "content-length"))
+	r.Nil(HeaderKeyValues(nil))
+}
@@ -0,0 +1,95 @@
+package client
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// 在密钥环中保存主密钥时使用的服务名称。
+const keyringService = "go-webapi-client"
+
+var errKeyringUnavailable = errors.New("the system keyring is not available")
+
+// 操作系统的密钥环，见 [SecretModeKeyring] 。
+type keyring interface {
+	Set(account, secret string) error
+	Get(account string) (string, error)
+}
+
+// 当前系统的密钥环，不可用时为 nil 。可在测试中替换。
+var systemKeyring = newCommandKeyring()
+
+// 通过命令行工具访问密钥环： macOS 使用 security ， Linux 使用 libsecret 的 secret-tool 。
+// 工具不存在时返回 nil 。即使工具存在，也可能因为没有运行密钥环服务（如无图形界面的 Linux ）而失败。
+func newCommandKeyring() keyring {
+	var tool string
+	switch runtime.GOOS {
+	case "darwin":
+		tool = "security"
+	case "linux", "freebsd", "openbsd", "netbsd":
+		tool = "secret-tool"
+	default:
+		return nil
+	}
+
+	if _, err := exec.LookPath(tool); err != nil {
+		return nil
+	}
+	return commandKeyring(tool)
+}
+
+// 值为命令行工具的名称。
+type commandKeyring string
+
+func (x commandKeyring) Set(account, secret string) error {
+	var cmd *exec.Cmd
+	if x == "security" {
+		// security 不能从标准输入读取密码，密钥会短暂地出现在进程的参数中。
+		cmd = exec.Command("security", "add-generic-password", "-U", "-s", keyringService, "-a", account, "-w", secret)
+	} else {
+		cmd = exec.Command("secret-tool", "store", "--label", keyringService, "service", keyringService, "account", account)
+		cmd.Stdin = strings.NewReader(secret)
+	}
+
+	_, err := x.run(cmd)
+	return err
+}
+
+func (x commandKeyring) Get(account string) (string, error) {
+	var cmd *exec.Cmd
+	if x == "security" {
+		cmd = exec.Command("security", "find-generic-password", "-s", keyringService, "-a", account, "-w")
+	} else {
+		cmd = exec.Command("secret-tool", "lookup", "service", keyringService, "account", account)
+	}
+
+	out, err := x.run(cmd)
+	if err != nil {
+		return "", err
+	}
+
+	if out == "" {
+		return "", fmt.Errorf("no master key found in the keyring for %s", account)
+	}
+	return out, nil
+}
+
+func (x commandKeyring) run(cmd *exec.Cmd) (string, error) {
+	stdout := new(bytes.Buffer)
+	stderr := new(bytes.Buffer)
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+
+	if err := cmd.Run(); err != nil {
+		msg := strings.TrimSpace(stderr.String())
+		if msg == "" {
+			return "", fmt.Errorf("%s: %w", x, err)
+		}
+		return "", fmt.Errorf("%s: %w: %s", x, err, msg)
+	}
+	return strings.TrimSpace(stdout.String()), nil
+}
@@ -1,7 +1,10 @@
 package client
 
 import (
+	"errors"
 	"fmt"
+	"strings"
+	"sync"
 
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/app"
@@ -18,14 +21,17 @@ import (
 ----------------------------------------------
 |<WindowTitle>                               |
 |--------------------------------------------| <-|
-|Client Config Help                          |   <Menu>
+|Client Settings                             |   <Menu>
 |--------------------------------------------| <-|
 |ClientTitle |                               |   |
+|Environment |                               |   |
+|Configs|Hist|                               |   |
+|Search      |                               |   |
 |ConfigList  |                               |   |
-| |- config1 |                               |   |
+| |- folder1 |                               |   |
+| |  |- cfg1 |                               |   |
 | |- config2 |                               |   |
-| |- config3 |                               |   |
-| |- config4 |         client.Box()          |   <MainContent>
+| |- config3 |         client.Box()          |   <MainContent>
 |            |                               |   |
 |------------|                               |   |
 |Config      |                               |   |
@@ -39,11 +45,16 @@ import (
 		<WindowTitle>				窗体标题，可跟着选中的 Client 变化。
 		<Menu>						菜单。
 			<Client>				可以在这个菜单里选择要展示哪个 Client ，每个 Client 一个菜单项。
+			<Configs>				将多个 Client 的配置打包导出，或导入配置包，详见 main_window_bundle.go 。
+			<Settings>				全局设置，如默认的连接参数（超时、代理、 TLS ），敏感字段的加密存储（详见 main_window_secrets.go ）。
 		<MainContent>				窗体的主容器，当前展示的 Client 的配置和主界面。
 			<ConfigArea>			当前 Client 的配置。
 				<ClientTitle>		展示当前的 Client.Title() 。
-				<ConfigList>		当前 Client 的配置列表，每个 Client 可以有一组配置，基于 Client.Name() 从配置文件里获取。
-				<ConfigOperation>	对于当前配置的操作：保存、删除、移动。
+				<Environment>		选择当前 Client 使用的环境，环境中的变量可在 Client 的各字段中以 {{name}} 的形式引用。
+				<ConfigSearch>		位于 Configs 标签页，按名称或内容过滤 <ConfigList> ，详见 main_window_search.go 。
+				<ConfigList>		位于 Configs 标签页，当前 Client 的配置树，每个 Client 可以有一组配置，基于 Client.Name() 从配置文件里获取，可放在多级目录中；有未保存改动的配置带有 * 标记，详见 main_window_changes.go ；配置目录在程序外发生变化时自动刷新，详见 main_window_watch.go 。
+				<ConfigOperation>	对于当前配置或目录的操作：保存、删除、新建目录、移动到其他目录，将当前的请求导出为 curl/Go/HTTPie 代码片段，从 curl 命令导入。
+				<HistoryPanel>		位于 History 标签页，当前 Client 的请求历史，可重放或另存为配置，详见 main_window_history.go 。
 			<ClientBox>				展示当前的 Client.Box() 。
 */
 
@@ -56,14 +67,37 @@ type MainWindow struct {
 	height        float32
 	clients       []Client
 
+	// 保护 configAreaData 的 configTree 、 query 、 matches 、 matchIndex 和 clientBoxData.client ，
+	// 它们也会在后台的 goroutine 中被读写，见 main_window_watch.go 和 main_window_changes.go 。
+	// 这些字段只在加锁时修改；在界面的事件中读取 clientBoxData.client 时无需加锁，因为只有界面的事件会修改它。
+	stateMu sync.Mutex
+
 	// <ConfigArea> 的数据，每次切换 Client 时初始化。
 	configAreaData struct {
-		title       binding.String             // 绑定当前 Client 的 Title() 。
-		configKeys  []string                   // 当前 Client 的所有配置的 key 。
-		keys        binding.ExternalStringList // 绑定 configKeys 。
-		selectedKey binding.String             // configKeys 中当前被选中的 key 。
+		title          binding.String // 绑定当前 Client 的 Title() 。
+		configTree     *configTree    // 当前 Client 的所有配置和目录。
+		tree           *widget.Tree   // 展示 configTree 。
+		selectedKey    binding.String // 当前被选中的配置的 key ，可在输入框中编辑，以目录的路径开头时保存到该目录下。
+		selectedFolder string         // 当前被选中的目录，选中的是配置时为空字符串。
+		envSelect      *widget.Select // 选择当前 Client 使用的环境。
+
+		// 搜索框，详见 main_window_search.go 。
+		search         *searchEntry
+		searchContents *widget.Check // 是否搜索配置的内容。
+		query          searchQuery   // 搜索框的内容，供后台的 goroutine 读取。
+		matches        []string      // 搜索结果，按匹配程度排列。
+		matchIndex     int           // 通过方向键选中的搜索结果的索引，尚未选中时为 -1 。
 	}
 
+	// <HistoryPanel> 的数据，每次切换 Client 时初始化。
+	historyData historyPanelData
+
+	// 跟踪 <ClientBox> 上的配置是否有未保存的改动，详见 main_window_changes.go 。
+	changes changeTracker
+
+	// 磁盘上的配置变化，详见 main_window_watch.go 。
+	watchData configWatchData
+
 	// <ClientBox> 的数据，每次切换 Client 时初始化。
 	clientBoxData struct {
 		client    Client          // 当前的 Client 。
@@ -101,7 +135,7 @@ func NewMainWindow(op *MainWindowOption) *MainWindow {
 	}
 	m.configAreaData.title = binding.NewString()
 	m.configAreaData.selectedKey = binding.NewString()
-	m.configAreaData.keys = binding.BindStringList(&[]string{})
+	m.configAreaData.configTree = newConfigTree(nil, nil)
 	m.clientBoxData.container = container.NewMax()
 
 	if m.width <= 0 {
@@ -109,11 +143,38 @@ func NewMainWindow(op *MainWindowOption) *MainWindow {
 	}
 
 	if m.height <= 0 {
-		m.width = DefaultWindowHeight
+		m.height = DefaultWindowHeight
+	}
+
+	// 全局默认的连接参数，读取失败时使用零值，待窗口展示后再提示。
+	transportDefaults, transportErr := m.configManager.LoadTransportDefaults()
+	SetDefaultTransportOption(transportDefaults)
+
+	// 各 Client 需要加密保存的字段。
+	for _, c := range m.clients {
+		if marker, ok := c.(SecretMarker); ok {
+			m.configManager.SetSecretKeys(c.Name(), marker.SecretKeys())
+		}
+	}
+
+	// 接收各 Client 报告的请求历史。
+	for _, c := range m.clients {
+		if reporter, ok := c.(HistoryReporter); ok {
+			clientName := c.Name()
+			reporter.SetHistoryHandler(func(entry HistoryEntry) {
+				m.onHistory(clientName, entry)
+			})
+		}
 	}
 
 	w.SetMainMenu(m.makeMenu())
 	w.SetContent(m.makeMainContent())
+
+	if transportErr != nil {
+		m.showError(transportErr)
+	}
+
+	m.unlockSecretsOnStart()
 	return m
 }
 
@@ -121,24 +182,71 @@ func NewMainWindow(op *MainWindowOption) *MainWindow {
 func (x *MainWindow) ShowAndRun() {
 	x.showClient(x.clients[0])
 	x.win.Resize(fyne.NewSize(x.width, x.height))
+	x.win.SetCloseIntercept(func() {
+		x.confirmDiscard(x.win.Close, nil)
+	})
+
+	stop := x.watchChanges()
+	stopWatch := x.watchConfigDir()
 	x.win.ShowAndRun()
+	stopWatch()
+	stop()
 }
 
 func (x *MainWindow) makeMenu() *fyne.MainMenu {
 	clientItems := make([]*fyne.MenuItem, 0, len(x.clients))
 	for _, c := range x.clients {
 		menu := fyne.NewMenuItem(c.Name(), func() {
-			x.showClient(c)
+			x.confirmDiscard(func() {
+				x.showClient(c)
+			}, nil)
 		})
 		clientItems = append(clientItems, menu)
 	}
 
 	mainMenu := fyne.NewMainMenu(
 		fyne.NewMenu("Client", clientItems...),
+		fyne.NewMenu("Configs",
+			fyne.NewMenuItem("Export bundle...", x.showExportBundleDialog),
+			fyne.NewMenuItem("Import bundle...", x.showImportBundleDialog),
+		),
+		fyne.NewMenu("Settings",
+			fyne.NewMenuItem("Transport defaults...", x.showTransportDefaultsEditor),
+			fyne.NewMenuItem("Secrets...", x.showSecretsDialog),
+		),
 	)
 	return mainMenu
 }
 
+// 编辑全局默认的连接参数，各 Client 的配置中未指定的部分使用这些值。
+func (x *MainWindow) showTransportDefaultsEditor() {
+	settings := NewTransportSettings()
+	settings.SetOption(DefaultTransportOption())
+
+	callback := func(ok bool) {
+		if !ok {
+			return
+		}
+
+		op, err := settings.Option()
+		if err != nil {
+			x.showError(err)
+			return
+		}
+
+		err = x.configManager.SaveTransportDefaults(op)
+		if err != nil {
+			x.showError(err)
+			return
+		}
+		SetDefaultTransportOption(op)
+	}
+
+	d := dialog.NewForm("Transport defaults", "SAVE", "CANCEL", settings.FormItems(), callback, x.win)
+	d.Resize(fyne.NewSize(x.width/2, 0))
+	d.Show()
+}
+
 func (x *MainWindow) makeMainContent() fyne.CanvasObject {
 	content := container.NewHSplit(
 		x.makeConfigArea(),
@@ -151,53 +259,172 @@ func (x *MainWindow) makeMainContent() fyne.CanvasObject {
 }
 
 func (x *MainWindow) makeConfigArea() fyne.CanvasObject {
-	createListItem := func() fyne.CanvasObject {
+	childUIDs := func(id widget.TreeNodeID) []widget.TreeNodeID {
+		return x.currentConfigTree().children[id]
+	}
+	createTreeItem := func(branch bool) fyne.CanvasObject {
 		// 每项样式为： [ICON] LABEL
-		return container.NewHBox(widget.NewIcon(theme.DocumentIcon()), widget.NewLabel(""))
+		icon := theme.DocumentIcon()
+		if branch {
+			icon = theme.FolderIcon()
+		}
+		return container.NewHBox(widget.NewIcon(icon), widget.NewLabel(""))
 	}
-	updateListItem := func(i binding.DataItem, o fyne.CanvasObject) {
-		// o 对应上面 createListItem 返回的容器，索引0为 ICON ，索引1为 LABEL 部分。
+	updateTreeItem := func(id widget.TreeNodeID, branch bool, o fyne.CanvasObject) {
+		// o 对应上面 createTreeItem 返回的容器，索引0为 ICON ，索引1为 LABEL 部分。
 		label := o.(*fyne.Container).Objects[1].(*widget.Label)
-		label.Bind(i.(binding.String))
+		text := x.currentConfigTree().label(id)
+		if !branch && x.changes.isDirty(id) {
+			text = modifiedMark + text
+		}
+		label.SetText(text)
 	}
-	list := widget.NewListWithData(x.configAreaData.keys, createListItem, updateListItem)
+	tree := widget.NewTree(childUIDs, configTreeIsFolder, createTreeItem, updateTreeItem)
+	x.configAreaData.tree = tree
 
-	// 当选中一个配置，将配置应用到 <ClientBox> 上。
-	list.OnSelected = func(id widget.ListItemID) {
-		item, _ := x.configAreaData.keys.GetItem(id)
-		key, _ := item.(binding.String).Get()
+	// 当选中一个配置，将配置应用到 <ClientBox> 上；选中目录时，记录下来用于 <ConfigOperation> 。
+	// 当前的配置有未保存的改动时，需先确认保存或放弃，取消时恢复原来的选中项。
+	tree.OnSelected = func(id widget.TreeNodeID) {
+		if configTreeIsFolder(id) {
+			x.configAreaData.selectedFolder = configTreeFolderOf(id)
+			return
+		}
 
-		conf := x.configManager.Load(x.clientBoxData.client.Name(), key)
-		if conf == nil {
-			panic("cannot load the config, the file may have been removed")
+		key := id
+		x.configAreaData.selectedFolder = ""
+		if key == x.changes.loadedKey() {
+			return
 		}
 
-		x.configAreaData.selectedKey.Set(key)
-		x.clientBoxData.client.SetConfig(conf)
+		load := func() {
+			clientName := x.clientBoxData.client.Name()
+			conf, err := x.configManager.Load(clientName, key)
+			if err != nil {
+				// 文件可能已在外部被删除，刷新列表使其与磁盘一致。
+				if errors.Is(err, ErrConfigNotFound) {
+					x.reloadConfig(clientName)
+				}
+
+				x.showError(err)
+				return
+			}
+
+			x.clientBoxData.client.SetConfig(conf)
+			x.setLoadedKey(key)
+		}
+
+		restore := func() {
+			if loaded := x.changes.loadedKey(); loaded != "" {
+				tree.Select(loaded)
+			} else {
+				tree.UnselectAll()
+			}
+		}
+
+		x.confirmDiscard(load, restore)
 	}
 
-	return container.NewBorder(
-		/* top		*/ widget.NewLabelWithData(x.configAreaData.title),
+	top := container.NewVBox(
+		widget.NewLabelWithData(x.configAreaData.title),
+		x.makeEnvironmentSelector(),
+	)
+
+	configTab := container.NewBorder(
+		/* top		*/ x.makeConfigSearch(),
 		/* bottom	*/ x.makeConfigOperation(),
 		/* left		*/ nil,
 		/* right	*/ nil,
-		/* center	*/ list,
+		/* center	*/ tree,
+	)
+
+	tabs := container.NewAppTabs(
+		container.NewTabItem("Configs", configTab),
+		container.NewTabItem("History", x.makeHistoryPanel()),
+	)
+
+	return container.NewBorder(
+		/* top		*/ top,
+		/* bottom	*/ nil,
+		/* left		*/ nil,
+		/* right	*/ nil,
+		/* center	*/ tabs,
 	)
 }
 
 func (x *MainWindow) makeConfigOperation() fyne.CanvasObject {
+	// 保存到输入框中的 key 。 key 不是当前载入的配置时，等同于另存为，目标已存在时需确认覆盖。
 	btnSave := widget.NewButton("SAVE", func() {
 		key, _ := x.configAreaData.selectedKey.Get()
 		if key == "" {
 			return
 		}
 
-		c := x.clientBoxData.client
-		x.configManager.Save(c.Name(), key, c.GetConfig())
-		x.reloadConfig(c.Name())
+		if key == x.changes.loadedKey() {
+			x.saveConfigAs(key, true)
+			return
+		}
+
+		x.confirmOverwrite(key, func(overwrite bool) {
+			x.saveConfigAs(key, overwrite)
+		})
+	})
+
+	btnSaveAs := widget.NewButton("SAVE AS...", func() {
+		key, _ := x.configAreaData.selectedKey.Get()
+		x.showConfigKeyDialog("Save as", "SAVE", key, func(newKey string) {
+			x.confirmOverwrite(newKey, func(overwrite bool) {
+				x.saveConfigAs(newKey, overwrite)
+			})
+		})
+	})
+
+	btnRename := widget.NewButton("RENAME...", func() {
+		key := x.changes.loadedKey()
+		if key == "" {
+			return
+		}
+
+		x.showConfigKeyDialog("Rename config", "RENAME", key, func(newKey string) {
+			x.confirmOverwrite(newKey, func(overwrite bool) {
+				c := x.clientBoxData.client
+				err := x.configManager.Rename(c.Name(), key, newKey, overwrite)
+				if err != nil {
+					x.showError(err)
+					return
+				}
+
+				x.changes.setKey(newKey)
+				x.configAreaData.selectedKey.Set(newKey)
+				x.reloadConfig(c.Name())
+			})
+		})
+	})
+
+	btnDuplicate := widget.NewButton("DUPLICATE...", func() {
+		key := x.changes.loadedKey()
+		if key == "" {
+			return
+		}
+
+		x.showConfigKeyDialog("Duplicate config", "DUPLICATE", key+" copy", func(newKey string) {
+			x.confirmOverwrite(newKey, func(overwrite bool) {
+				c := x.clientBoxData.client
+				err := x.configManager.Duplicate(c.Name(), key, newKey, overwrite)
+				if err != nil {
+					x.showError(err)
+					return
+				}
+				x.reloadConfig(c.Name())
+			})
+		})
 	})
 
 	btnDelete := widget.NewButton("DELETE", func() {
+		if folder := x.configAreaData.selectedFolder; folder != "" {
+			x.deleteFolder(folder)
+			return
+		}
+
 		key, _ := x.configAreaData.selectedKey.Get()
 		if key == "" {
 			return
@@ -209,34 +436,638 @@ func (x *MainWindow) makeConfigOperation() fyne.CanvasObject {
 			}
 
 			c := x.clientBoxData.client
-			x.configManager.Remove(c.Name(), key)
+			err := x.configManager.Remove(c.Name(), key)
+			if err != nil {
+				x.showError(err)
+				return
+			}
 
+			if key == x.changes.loadedKey() {
+				x.changes.setKey("")
+			}
 			x.reloadConfig(c.Name())
 		}
 		msg := fmt.Sprintf("Delete config >> %s <<?", key)
 		dialog.ShowConfirm("Confirm deletion", msg, callback, x.win)
 	})
 
+	btnNewFolder := widget.NewButton("NEW FOLDER...", x.showNewFolderDialog)
+	btnMove := widget.NewButton("MOVE TO...", x.showMoveDialog)
+	btnExport := widget.NewButton("EXPORT...", x.showExportDialog)
+	btnImport := widget.NewButton("IMPORT...", x.showImportDialog)
+
 	return container.NewVBox(
 		widget.NewSeparator(),
 		widget.NewEntryWithData(x.configAreaData.selectedKey),
-		container.NewGridWithColumns(2, btnSave, btnDelete),
+		container.NewGridWithColumns(2,
+			btnSave, btnSaveAs,
+			btnRename, btnDuplicate,
+			btnDelete, btnMove,
+			btnNewFolder, btnExport,
+			btnImport,
+		),
 	)
 }
 
+// 将 <ClientBox> 上的配置保存为 key ，并作为当前载入的配置。返回是否保存成功。
+func (x *MainWindow) saveConfigAs(key string, overwrite bool) bool {
+	c := x.clientBoxData.client
+	err := x.configManager.SaveAs(c.Name(), key, c.GetConfig(), overwrite)
+	if err != nil {
+		x.showError(err)
+		return false
+	}
+
+	x.setLoadedKey(key)
+	x.reloadConfig(c.Name())
+	return true
+}
+
+// 设置当前载入的配置，并在输入框中展示其 key 。应在配置应用到 <ClientBox> 上之后调用，
+// 此时的配置作为判断是否有改动的基准。
+func (x *MainWindow) setLoadedKey(key string) {
+	x.changes.reset(x.currentClient(), key)
+	x.configAreaData.selectedKey.Set(key)
+	x.configAreaData.tree.Refresh()
+}
+
+// 当前 Client 下 key 对应的配置已存在时，确认后以 overwrite=true 调用 action ；不存在时以 overwrite=false 调用。
+func (x *MainWindow) confirmOverwrite(key string, action func(overwrite bool)) {
+	x.confirmOverwriteFor(x.clientBoxData.client, key, action)
+}
+
+// 同 confirmOverwrite ，但检查给定 Client 下的配置。
+func (x *MainWindow) confirmOverwriteFor(c Client, key string, action func(overwrite bool)) {
+	exists, err := x.configManager.Exists(c.Name(), key)
+	if err != nil {
+		x.showError(err)
+		return
+	}
+
+	if !exists {
+		action(false)
+		return
+	}
+
+	msg := fmt.Sprintf("Config >> %s << already exists, overwrite it?", key)
+	dialog.ShowConfirm("Confirm overwrite", msg, func(ok bool) {
+		if ok {
+			action(true)
+		}
+	}, x.win)
+}
+
+// 输入配置的 key 的对话框，可以以目录的路径开头。 key 为空或未变化时不调用 onKey 。
+func (x *MainWindow) showConfigKeyDialog(title, confirm, initial string, onKey func(key string)) {
+	keyInput := widget.NewEntry()
+	keyInput.SetText(initial)
+	items := []*widget.FormItem{
+		{Text: "Name", Widget: keyInput, HintText: "use / to put the config in a folder, e.g. folder/name"},
+	}
+
+	callback := func(ok bool) {
+		if !ok || keyInput.Text == "" || keyInput.Text == initial {
+			return
+		}
+		onKey(keyInput.Text)
+	}
+
+	d := dialog.NewForm(title, confirm, "CANCEL", items, callback, x.win)
+	d.Resize(fyne.NewSize(x.width/3, 0))
+	d.Show()
+}
+
+// 删除目录及其中所有的配置。
+func (x *MainWindow) deleteFolder(folder string) {
+	callback := func(ok bool) {
+		if !ok {
+			return
+		}
+
+		c := x.clientBoxData.client
+		err := x.configManager.RemoveFolder(c.Name(), folder)
+		if err != nil {
+			x.showError(err)
+			return
+		}
+
+		x.configAreaData.selectedFolder = ""
+		x.configAreaData.tree.UnselectAll()
+		x.reloadConfig(c.Name())
+	}
+	msg := fmt.Sprintf("Delete folder >> %s << and all configs in it?", folder)
+	dialog.ShowConfirm("Confirm deletion", msg, callback, x.win)
+}
+
+// 在当前选中的目录下新建目录，名称中可以用 / 一次创建多级目录。
+func (x *MainWindow) showNewFolderDialog() {
+	parent := x.configAreaData.selectedFolder
+	if parent == "" {
+		key, _ := x.configAreaData.selectedKey.Get()
+		parent, _ = SplitConfigKey(key)
+	}
+
+	nameInput := widget.NewEntry()
+	items := []*widget.FormItem{
+		{Text: "Parent", Widget: widget.NewLabel("/" + parent)},
+		{Text: "Name", Widget: nameInput, HintText: "use / to create nested folders"},
+	}
+
+	callback := func(ok bool) {
+		if !ok {
+			return
+		}
+
+		c := x.clientBoxData.client
+		folder := JoinConfigKey(parent, nameInput.Text)
+		err := x.configManager.CreateFolder(c.Name(), folder)
+		if err != nil {
+			x.showError(err)
+			return
+		}
+
+		x.reloadConfig(c.Name())
+		x.openFolder(folder)
+	}
+
+	d := dialog.NewForm("New folder", "CREATE", "CANCEL", items, callback, x.win)
+	d.Resize(fyne.NewSize(x.width/3, 0))
+	d.Show()
+}
+
+// 将当前选中的目录或配置移动到另一个目录下。
+func (x *MainWindow) showMoveDialog() {
+	c := x.clientBoxData.client
+	folder := x.configAreaData.selectedFolder
+	key, _ := x.configAreaData.selectedKey.Get()
+
+	source := folder
+	if source == "" {
+		source = key
+	}
+	if source == "" {
+		return
+	}
+
+	// 目录不能移动到其自身或其子目录下。
+	const root = "/"
+	targets := []string{root}
+	for _, v := range x.currentConfigTree().folders() {
+		if folder != "" && (v == folder || strings.HasPrefix(v, folder+"/")) {
+			continue
+		}
+		targets = append(targets, v)
+	}
+
+	targetSelect := widget.NewSelect(targets, nil)
+	targetSelect.SetSelected(root)
+	items := []*widget.FormItem{
+		{Text: "Move", Widget: widget.NewLabel(source)},
+		{Text: "To", Widget: targetSelect},
+	}
+
+	callback := func(ok bool) {
+		if !ok {
+			return
+		}
+
+		target := targetSelect.Selected
+		if target == root {
+			target = ""
+		}
+
+		if folder != "" {
+			moved, err := x.configManager.MoveFolder(c.Name(), folder, target)
+			if err != nil {
+				x.showError(err)
+				return
+			}
+
+			x.configAreaData.selectedFolder = ""
+			x.configAreaData.tree.UnselectAll()
+			x.reloadConfig(c.Name())
+			x.openFolder(moved)
+			return
+		}
+
+		moved, err := x.configManager.Move(c.Name(), key, target)
+		if err != nil {
+			x.showError(err)
+			return
+		}
+
+		x.reloadConfig(c.Name())
+		if key == x.changes.loadedKey() {
+			x.changes.setKey(moved)
+			x.configAreaData.selectedKey.Set(moved)
+		} else {
+			x.configAreaData.selectedKey.Set(moved)
+		}
+		x.openFolder(target)
+	}
+
+	d := dialog.NewForm("Move to folder", "MOVE", "CANCEL", items, callback, x.win)
+	d.Resize(fyne.NewSize(x.width/3, 0))
+	d.Show()
+}
+
+// 展开目录及其各级上级目录。
+func (x *MainWindow) openFolder(folder string) {
+	for folder != "" {
+		x.configAreaData.tree.OpenBranch(configTreeFolderID(folder))
+		folder, _ = SplitConfigKey(folder)
+	}
+}
+
+// 将当前 Client 界面上的请求导出为 curl 命令、 Go 代码等，见 [Exporter] 。
+// 签名在每次切换格式或选项时重新计算，使用当时的时间。
+func (x *MainWindow) showExportDialog() {
+	c := x.clientBoxData.client
+	exporter, ok := c.(Exporter)
+	if !ok {
+		dialog.ShowInformation("Export", fmt.Sprintf("Client %s does not support exporting.", c.Name()), x.win)
+		return
+	}
+
+	// 敏感字段默认被替换为 [RedactedText] ，以免随代码片段被分享出去。
+	var sensitive []string
+	if marker, ok := c.(SecretMarker); ok {
+		vars, err := x.environmentVariables(x.configAreaData.envSelect.Selected)
+		if err != nil {
+			x.showError(err)
+			return
+		}
+		sensitive = SensitiveValues(c.GetConfig(), marker.SecretKeys(), vars)
+	}
+
+	output := widget.NewMultiLineEntry()
+	formatSelect := widget.NewSelect(ExportFormats, nil)
+	unsignedCheck := widget.NewCheck("Unsigned template, sign in the Go code", nil)
+	revealCheck := widget.NewCheck("Show secrets", nil)
+
+	refresh := func() {
+		req, err := exporter.ExportRequest(!unsignedCheck.Checked)
+		if err != nil {
+			output.SetText(err.Error())
+			return
+		}
+
+		snippet, err := ExportSnippet(formatSelect.Selected, req)
+		if err != nil {
+			output.SetText(err.Error())
+			return
+		}
+
+		if !revealCheck.Checked {
+			snippet = Redact(snippet, sensitive)
+		}
+		output.SetText(snippet)
+	}
+	formatSelect.OnChanged = func(string) { refresh() }
+	unsignedCheck.OnChanged = func(bool) { refresh() }
+	revealCheck.OnChanged = func(bool) { refresh() }
+	formatSelect.SetSelected(ExportFormatCurl)
+
+	if len(sensitive) == 0 {
+		revealCheck.Disable()
+	}
+
+	btnCopy := widget.NewButtonWithIcon("Copy", theme.ContentCopyIcon(), func() {
+		x.win.Clipboard().SetContent(output.Text)
+	})
+
+	content := container.NewBorder(
+		/* top		*/ container.NewHBox(formatSelect, unsignedCheck, revealCheck),
+		/* bottom	*/ btnCopy,
+		/* left		*/ nil,
+		/* right	*/ nil,
+		/* center	*/ output,
+	)
+
+	d := dialog.NewCustom("Export as...", "CLOSE", content, x.win)
+	d.Resize(fyne.NewSize(x.width*0.7, x.height*0.7))
+	d.Show()
+}
+
+// 从 curl 命令导入请求，保存为新的配置，见 [Importer] 。
+// 根据命令的内容自动选择 Client ，如带有 SLIM-AUTH 签名的请求导入到 SlimAuth 。
+func (x *MainWindow) showImportDialog() {
+	importers := make(map[string]Importer)
+	clients := make(map[string]Client)
+	var names []string
+	for _, c := range x.clients {
+		if importer, ok := c.(Importer); ok {
+			importers[c.Name()] = importer
+			clients[c.Name()] = c
+			names = append(names, c.Name())
+		}
+	}
+
+	if len(names) == 0 {
+		dialog.ShowInformation("Import", "No client supports importing.", x.win)
+		return
+	}
+
+	commandInput := widget.NewMultiLineEntry()
+	commandInput.SetPlaceHolder("curl -X POST 'http://...' -H '...' -d '...'")
+	commandInput.SetMinRowsVisible(8)
+
+	clientSelect := widget.NewSelect(names, nil)
+	if _, ok := importers[x.clientBoxData.client.Name()]; ok {
+		clientSelect.SetSelected(x.clientBoxData.client.Name())
+	} else {
+		clientSelect.SetSelected(names[0])
+	}
+
+	// 粘贴命令后，选中第一个匹配的 Client 。
+	commandInput.OnChanged = func(text string) {
+		req, err := ParseCurl(text)
+		if err != nil {
+			return
+		}
+
+		for _, name := range names {
+			if importers[name].MatchRequest(req) {
+				clientSelect.SetSelected(name)
+				return
+			}
+		}
+	}
+
+	nameInput := widget.NewEntry()
+	nameInput.SetPlaceHolder("the name of the new config")
+
+	items := []*widget.FormItem{
+		{Text: "curl", Widget: commandInput},
+		{Text: "Client", Widget: clientSelect},
+		{Text: "Name", Widget: nameInput},
+	}
+
+	save := func(c Client, key string, conf map[string]any, overwrite bool) {
+		err := x.configManager.SaveAs(c.Name(), key, conf, overwrite)
+		if err != nil {
+			x.showError(err)
+			return
+		}
+
+		if x.clientBoxData.client != c {
+			x.showClient(c)
+		} else {
+			x.reloadConfig(c.Name())
+		}
+
+		c.SetConfig(conf)
+		x.setLoadedKey(key)
+	}
+
+	callback := func(ok bool) {
+		if !ok {
+			return
+		}
+
+		req, err := ParseCurl(commandInput.Text)
+		if err != nil {
+			x.showError(err)
+			return
+		}
+
+		c := clients[clientSelect.Selected]
+		conf, err := importers[c.Name()].ImportRequest(req)
+		if err != nil {
+			x.showError(err)
+			return
+		}
+
+		key := nameInput.Text
+		x.confirmOverwriteFor(c, key, func(overwrite bool) {
+			x.confirmDiscard(func() {
+				save(c, key, conf, overwrite)
+			}, nil)
+		})
+	}
+
+	d := dialog.NewForm("Import from curl", "IMPORT", "CANCEL", items, callback, x.win)
+	d.Resize(fyne.NewSize(x.width*0.6, x.height/2))
+	d.Show()
+}
+
+// 空环境，选中时不使用任何变量。
+const _NO_ENVIRONMENT = "(no environment)"
+
+func (x *MainWindow) makeEnvironmentSelector() fyne.CanvasObject {
+	envSelect := widget.NewSelect(nil, func(name string) {
+		x.applyEnvironment(name)
+	})
+	envSelect.PlaceHolder = _NO_ENVIRONMENT
+	x.configAreaData.envSelect = envSelect
+
+	btnEdit := widget.NewButtonWithIcon("", theme.DocumentCreateIcon(), x.showEnvironmentEditor)
+	btnDelete := widget.NewButtonWithIcon("", theme.DeleteIcon(), func() {
+		name := envSelect.Selected
+		if name == "" || name == _NO_ENVIRONMENT {
+			return
+		}
+
+		callback := func(ok bool) {
+			if !ok {
+				return
+			}
+
+			c := x.clientBoxData.client
+			err := x.configManager.RemoveEnvironment(c.Name(), name)
+			if err != nil {
+				x.showError(err)
+				return
+			}
+			x.reloadEnvironments(c.Name(), "")
+		}
+		msg := fmt.Sprintf("Delete environment >> %s <<?", name)
+		dialog.ShowConfirm("Confirm deletion", msg, callback, x.win)
+	})
+
+	return container.NewBorder(nil, nil, nil, container.NewHBox(btnEdit, btnDelete), envSelect)
+}
+
+// 编辑当前选中的环境，若没有选中，则新建一个环境。
+func (x *MainWindow) showEnvironmentEditor() {
+	c := x.clientBoxData.client
+	envs, err := x.configManager.LoadEnvironments(c.Name())
+	if err != nil {
+		x.showError(err)
+		return
+	}
+
+	name := x.configAreaData.envSelect.Selected
+	if name == _NO_ENVIRONMENT {
+		name = ""
+	}
+
+	nameInput := widget.NewEntry()
+	nameInput.SetText(name)
+
+	varsInput := widget.NewMultiLineEntry()
+	varsInput.SetPlaceHolder("name=value, one per line; use {{name}} in the fields")
+	varsInput.SetText(formatVariables(envs[name]))
+	varsInput.SetMinRowsVisible(10)
+
+	items := []*widget.FormItem{
+		{Text: "Name", Widget: nameInput},
+		{Text: "Variables", Widget: varsInput},
+	}
+
+	callback := func(ok bool) {
+		if !ok {
+			return
+		}
+
+		vars, err := parseVariables(varsInput.Text)
+		if err != nil {
+			x.showError(err)
+			return
+		}
+
+		err = x.configManager.SaveEnvironment(c.Name(), nameInput.Text, vars)
+		if err != nil {
+			x.showError(err)
+			return
+		}
+		x.reloadEnvironments(c.Name(), nameInput.Text)
+	}
+
+	d := dialog.NewForm("Environment", "SAVE", "CANCEL", items, callback, x.win)
+	d.Resize(fyne.NewSize(x.width/2, x.height/2))
+	d.Show()
+}
+
+// 重新读取环境列表，并选中给定的环境。 selected 为空时，不使用任何环境。
+func (x *MainWindow) reloadEnvironments(clientName, selected string) {
+	names, err := x.configManager.ListEnvironments(clientName)
+	if err != nil {
+		x.showError(err)
+	}
+
+	x.configAreaData.envSelect.Options = append([]string{_NO_ENVIRONMENT}, names...)
+	if selected == "" {
+		x.configAreaData.envSelect.ClearSelected()
+		x.applyEnvironment("")
+	} else {
+		x.configAreaData.envSelect.SetSelected(selected)
+	}
+	x.configAreaData.envSelect.Refresh()
+}
+
+// 将给定环境的变量应用到当前的 Client 上。
+func (x *MainWindow) applyEnvironment(name string) {
+	setter, ok := x.clientBoxData.client.(VariableSetter)
+	if !ok {
+		return
+	}
+
+	vars, err := x.environmentVariables(name)
+	if err != nil {
+		x.showError(err)
+		return
+	}
+	setter.SetVariables(vars)
+}
+
+// 返回当前 Client 下给定环境的变量。未选择环境时返回 nil 。
+func (x *MainWindow) environmentVariables(name string) (map[string]string, error) {
+	if name == "" || name == _NO_ENVIRONMENT {
+		return nil, nil
+	}
+
+	envs, err := x.configManager.LoadEnvironments(x.clientBoxData.client.Name())
+	if err != nil {
+		return nil, err
+	}
+	return envs[name], nil
+}
+
 func (x *MainWindow) showClient(client Client) {
 	x.reloadConfig(client.Name())
 
 	x.configAreaData.title.Set(client.Title())
-	x.configAreaData.selectedKey.Set("")
+	x.configAreaData.selectedFolder = ""
+	x.configAreaData.tree.UnselectAll()
 
 	// 如果 container.Objects 没有发生变化， fyne 不会刷新界面。
+	x.stateMu.Lock()
 	x.clientBoxData.client = client
+	x.stateMu.Unlock()
 	x.clientBoxData.container.Objects = []fyne.CanvasObject{client.Box()}
+	x.setLoadedKey("")
+
+	// 搜索框不跨 Client 保留，清空时会重新加载配置列表。
+	if x.configAreaData.search.Text != "" {
+		x.configAreaData.search.SetText("")
+	}
+
+	x.reloadEnvironments(client.Name(), "")
+	x.reloadHistory(client.Name())
 }
 
 func (x *MainWindow) reloadConfig(clientName string) {
-	keys := x.configManager.ListKeys(clientName)
-	x.configAreaData.configKeys = keys
-	x.configAreaData.keys.Set(keys)
+	if x.searching() {
+		x.reloadSearch(clientName)
+		return
+	}
+
+	keys, err := x.configManager.ListKeys(clientName)
+	if err != nil {
+		x.showError(err)
+	}
+
+	folders, err := x.configManager.ListFolders(clientName)
+	if err != nil {
+		x.showError(err)
+	}
+
+	x.setConfigTree(newConfigTree(keys, folders))
+	x.configAreaData.tree.Refresh()
+}
+
+// 返回当前的 Client ，可在任意 goroutine 中调用。
+func (x *MainWindow) currentClient() Client {
+	x.stateMu.Lock()
+	defer x.stateMu.Unlock()
+	return x.clientBoxData.client
+}
+
+// 返回 <ConfigList> 展示的 configTree ，可在任意 goroutine 中调用。
+func (x *MainWindow) currentConfigTree() *configTree {
+	x.stateMu.Lock()
+	defer x.stateMu.Unlock()
+	return x.configAreaData.configTree
+}
+
+// 替换 <ConfigList> 展示的 configTree ，之后需刷新 configAreaData.tree 。
+// 刷新时会回调读取 configTree ，因此不能在持有锁时刷新。
+func (x *MainWindow) setConfigTree(tree *configTree) {
+	x.stateMu.Lock()
+	defer x.stateMu.Unlock()
+	x.configAreaData.configTree = tree
+}
+
+// 以对话框的形式展示 [ConfigManager] 等操作返回的错误，而不是让程序崩溃。
+func (x *MainWindow) showError(err error) {
+	var title string
+	switch {
+	case errors.Is(err, ErrInvalidName):
+		title = "Invalid config name"
+	case errors.Is(err, ErrCorruptConfig):
+		title = "Corrupt config file"
+	case errors.Is(err, ErrPermissionDenied):
+		title = "Permission denied"
+	case errors.Is(err, ErrConfigNotFound):
+		title = "Config not found"
+	case errors.Is(err, ErrConfigExists):
+		title = "Config already exists"
+	case errors.Is(err, ErrSecretsLocked):
+		title = "Secrets locked"
+	default:
+		title = "Error"
+	}
+
+	dialog.ShowInformation(title, err.Error(), x.win)
 }
@@ -0,0 +1,212 @@
+package client
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+/*
+配置包（见 bundle.go ）在主窗口上的操作，位于 Configs 菜单下：
+  - Export bundle... 选择若干个 Client 的配置，打包保存为文件。
+  - Import bundle... 选择配置包文件，按选择的方式处理已存在的配置。
+*/
+
+// 选择要打包的配置，保存为配置包文件。默认选中当前 Client 的所有配置。
+func (x *MainWindow) showExportBundleDialog() {
+	var options, selected []string
+	for _, c := range x.clients {
+		keys, err := x.configManager.ListKeys(c.Name())
+		if err != nil {
+			x.showError(err)
+			return
+		}
+
+		for _, key := range keys {
+			ref := ConfigRef{Client: c.Name(), Key: key}.String()
+			options = append(options, ref)
+			if c == x.clientBoxData.client {
+				selected = append(selected, ref)
+			}
+		}
+	}
+
+	if len(options) == 0 {
+		dialog.ShowInformation("Export bundle", "There is no config to export.", x.win)
+		return
+	}
+
+	configGroup := widget.NewCheckGroup(options, nil)
+	configGroup.SetSelected(selected)
+
+	allCheck := widget.NewCheck("Select all", func(checked bool) {
+		if checked {
+			configGroup.SetSelected(options)
+		} else {
+			configGroup.SetSelected(nil)
+		}
+	})
+
+	formatSelect := widget.NewSelect([]string{BundleFormatJson, BundleFormatZip}, nil)
+	formatSelect.SetSelected(BundleFormatJson)
+
+	stripCheck := widget.NewCheck("Strip secrets", nil)
+	stripCheck.SetChecked(true)
+
+	items := []*widget.FormItem{
+		{Text: "Configs", Widget: container.NewBorder(allCheck, nil, nil, nil, container.NewVScroll(configGroup))},
+		{Text: "Format", Widget: formatSelect},
+		{Text: "Secrets", Widget: stripCheck, HintText: "uncheck to include secret fields as plain text"},
+	}
+
+	callback := func(ok bool) {
+		if !ok || len(configGroup.Selected) == 0 {
+			return
+		}
+
+		refs := make([]ConfigRef, 0, len(configGroup.Selected))
+		for _, v := range configGroup.Selected {
+			ref, err := ParseConfigRef(v)
+			if err != nil {
+				x.showError(err)
+				return
+			}
+			refs = append(refs, ref)
+		}
+
+		bundle, err := x.configManager.ExportBundle(refs, stripCheck.Checked)
+		if err != nil {
+			x.showError(err)
+			return
+		}
+
+		buf := new(bytes.Buffer)
+		if err := WriteBundle(buf, formatSelect.Selected, bundle); err != nil {
+			x.showError(err)
+			return
+		}
+
+		x.saveBundleFile(buf.Bytes(), "configs."+formatSelect.Selected)
+	}
+
+	d := dialog.NewForm("Export bundle", "EXPORT", "CANCEL", items, callback, x.win)
+	d.Resize(fyne.NewSize(x.width/2, x.height*0.7))
+	d.Show()
+}
+
+func (x *MainWindow) saveBundleFile(content []byte, fileName string) {
+	d := dialog.NewFileSave(func(w fyne.URIWriteCloser, err error) {
+		if err != nil {
+			x.showError(err)
+			return
+		}
+
+		// 取消选择文件。
+		if w == nil {
+			return
+		}
+		defer w.Close()
+
+		if _, err := w.Write(content); err != nil {
+			x.showError(err)
+		}
+	}, x.win)
+
+	d.SetFileName(fileName)
+	d.Resize(fyne.NewSize(x.width*0.7, x.height*0.7))
+	d.Show()
+}
+
+// 选择配置包文件并导入。
+func (x *MainWindow) showImportBundleDialog() {
+	d := dialog.NewFileOpen(func(r fyne.URIReadCloser, err error) {
+		if err != nil {
+			x.showError(err)
+			return
+		}
+
+		// 取消选择文件。
+		if r == nil {
+			return
+		}
+		defer r.Close()
+
+		content, err := io.ReadAll(r)
+		if err != nil {
+			x.showError(err)
+			return
+		}
+
+		bundle, err := ReadBundle(content)
+		if err != nil {
+			x.showError(err)
+			return
+		}
+
+		x.showImportBundleOptions(bundle)
+	}, x.win)
+
+	d.Resize(fyne.NewSize(x.width*0.7, x.height*0.7))
+	d.Show()
+}
+
+func (x *MainWindow) showImportBundleOptions(bundle *Bundle) {
+	known := make(map[string]bool)
+	for _, c := range x.clients {
+		known[c.Name()] = true
+	}
+
+	// 按 Client 统计配置的数量，不认识的 Client 的配置也会被导入，但在界面上看不到。
+	counts := make(map[string]int)
+	for _, item := range bundle.Configs {
+		counts[item.Client]++
+	}
+
+	lines := make([]string, 0, len(counts))
+	for name, n := range counts {
+		line := fmt.Sprintf("%s: %d config(s)", name, n)
+		if !known[name] {
+			line += " (unknown client)"
+		}
+		lines = append(lines, line)
+	}
+	sort.Strings(lines)
+
+	conflictSelect := widget.NewSelect([]string{ConflictSkip, ConflictOverwrite, ConflictRename}, nil)
+	conflictSelect.SetSelected(ConflictSkip)
+
+	items := []*widget.FormItem{
+		{Text: "Bundle", Widget: widget.NewLabel(strings.Join(lines, "\n"))},
+		{Text: "If exists", Widget: conflictSelect, HintText: "rename saves the imported config as \"key (1)\""},
+	}
+
+	callback := func(ok bool) {
+		if !ok {
+			return
+		}
+
+		res, err := x.configManager.ImportBundle(bundle, conflictSelect.Selected)
+		if c := x.clientBoxData.client; c != nil {
+			x.reloadConfig(c.Name())
+		}
+
+		if err != nil {
+			x.showError(err)
+			return
+		}
+
+		msg := fmt.Sprintf("Imported: %d\nSkipped: %d\nRenamed: %d", len(res.Imported), len(res.Skipped), len(res.Renamed))
+		dialog.ShowInformation("Import bundle", msg, x.win)
+	}
+
+	d := dialog.NewForm("Import bundle", "IMPORT", "CANCEL", items, callback, x.win)
+	d.Resize(fyne.NewSize(x.width/3, 0))
+	d.Show()
+}
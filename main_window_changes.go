@@ -0,0 +1,134 @@
+package client
+
+import (
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+/*
+<ClientBox> 上未保存的改动：
+  - 比较 Client.GetConfig() 与最近一次载入或保存时的配置，有改动时在 <ConfigList> 中以 modifiedMark 标记当前的配置。
+    实现了 [ChangeNotifier] 的 Client 在界面上的配置被修改时比较，其余的定时比较。
+  - 切换配置、切换 Client 、从历史记录载入、导入和退出程序前，若有改动，提示保存或放弃。
+*/
+
+// <ConfigList> 中标记有未保存改动的配置。
+const modifiedMark = "* "
+
+// 定时检查改动的间隔，用于未实现 [ChangeNotifier] 的 Client 。
+const changeCheckInterval = 500 * time.Millisecond
+
+// 在后台检查改动，状态变化时刷新 <ConfigList> 。返回用于停止检查的函数。
+func (x *MainWindow) watchChanges() (stop func()) {
+	var stopped atomic.Bool // 回调无法移除，停止后忽略。
+	polling := false
+	for _, c := range x.clients {
+		notifier, ok := c.(ChangeNotifier)
+		if !ok {
+			polling = true
+			continue
+		}
+
+		c := c
+		notifier.AddChangeListener(func() {
+			if !stopped.Load() && x.currentClient() == c {
+				x.checkChanges()
+			}
+		})
+	}
+
+	if !polling {
+		return func() { stopped.Store(true) }
+	}
+
+	ticker := time.NewTicker(changeCheckInterval)
+	done := make(chan struct{})
+
+	go func() {
+		for {
+			select {
+			case <-ticker.C:
+				if _, ok := x.currentClient().(ChangeNotifier); !ok {
+					x.checkChanges()
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return func() {
+		stopped.Store(true)
+		ticker.Stop()
+		close(done)
+	}
+}
+
+// 检查改动，状态变化时刷新 <ConfigList> ，返回是否有改动。可在任意 goroutine 中调用。
+func (x *MainWindow) checkChanges() (dirty bool) {
+	dirty, changed := x.changes.check()
+	if changed {
+		x.configAreaData.tree.Refresh()
+	}
+	return dirty
+}
+
+// 若 <ClientBox> 上有未保存的改动，提示保存或放弃，之后调用 proceed ；没有改动时直接调用 proceed 。
+// 取消时调用 cancel （可以为 nil ）。
+func (x *MainWindow) confirmDiscard(proceed, cancel func()) {
+	if !x.checkChanges() {
+		proceed()
+		return
+	}
+
+	key := x.changes.loadedKey()
+	msg := "The current config has not been saved."
+	if key != "" {
+		msg = fmt.Sprintf("Config >> %s << has unsaved changes.", key)
+	}
+
+	var d dialog.Dialog
+	handled := false // 选择了保存或放弃，而不是取消。
+	btnSave := widget.NewButton("SAVE", func() {
+		handled = true
+		d.Hide()
+		if key != "" {
+			if x.saveConfigAs(key, true) {
+				proceed()
+			}
+			return
+		}
+
+		x.showConfigKeyDialog("Save as", "SAVE", "", func(newKey string) {
+			x.confirmOverwrite(newKey, func(overwrite bool) {
+				if x.saveConfigAs(newKey, overwrite) {
+					proceed()
+				}
+			})
+		})
+	})
+
+	btnDiscard := widget.NewButton("DISCARD", func() {
+		handled = true
+		d.Hide()
+		proceed()
+	})
+
+	content := container.NewVBox(
+		widget.NewLabel(msg),
+		container.NewGridWithColumns(2, btnSave, btnDiscard),
+	)
+
+	d = dialog.NewCustom("Unsaved changes", "CANCEL", content, x.win)
+	d.SetOnClosed(func() {
+		if !handled && cancel != nil {
+			cancel()
+		}
+	})
+	d.Show()
+}
@@ -0,0 +1,246 @@
+package client
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/data/binding"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+/*
+<HistoryPanel> 位于 <ConfigArea> 的 History 标签页，展示当前 Client 的请求历史：
+
+	<HistoryList>		历史记录列表，最新的在前。
+	<HistoryDetail>		选中的记录的详情：时间、地址、参数、状态码、耗时、响应。
+	<HistoryOperation>	对选中的记录的操作：载入、重放、另存为配置；以及清空历史。
+*/
+
+// <HistoryPanel> 的数据，每次切换 Client 时重新读取。
+// 请求完成后，历史记录在执行请求的 goroutine 中被重新读取，因此 entries 和 selected 需加锁访问。
+type historyPanelData struct {
+	mu       sync.Mutex
+	entries  []HistoryEntry // 当前 Client 的历史记录，最新的在前。
+	selected int            // 当前选中的记录在 entries 中的索引，未选中时为 -1 。
+	list     *widget.List
+	detail   binding.String
+}
+
+// 返回 entries 的长度。
+func (x *historyPanelData) count() int {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	return len(x.entries)
+}
+
+// 返回 entries 中索引为 id 的记录，索引无效时（如列表已被重新读取）返回 false 。
+func (x *historyPanelData) entry(id int) (HistoryEntry, bool) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	if id < 0 || id >= len(x.entries) {
+		return HistoryEntry{}, false
+	}
+	return x.entries[id], true
+}
+
+func (x *MainWindow) makeHistoryPanel() fyne.CanvasObject {
+	x.historyData.selected = -1
+	x.historyData.detail = binding.NewString()
+
+	list := widget.NewList(
+		func() int {
+			return x.historyData.count()
+		},
+		func() fyne.CanvasObject {
+			return container.NewHBox(widget.NewIcon(theme.HistoryIcon()), widget.NewLabel(""))
+		},
+		func(id widget.ListItemID, o fyne.CanvasObject) {
+			entry, ok := x.historyData.entry(id)
+			if !ok {
+				return
+			}
+
+			label := o.(*fyne.Container).Objects[1].(*widget.Label)
+			label.SetText(x.describeHistory(entry))
+		},
+	)
+	list.OnSelected = func(id widget.ListItemID) {
+		entry, ok := x.historyData.entry(id)
+		if !ok {
+			return
+		}
+
+		x.historyData.mu.Lock()
+		x.historyData.selected = id
+		x.historyData.mu.Unlock()
+		x.historyData.detail.Set(x.formatHistoryDetail(entry))
+	}
+	x.historyData.list = list
+
+	detail := widget.NewMultiLineEntry()
+	detail.Bind(x.historyData.detail)
+	detail.Wrapping = fyne.TextWrapBreak
+
+	content := container.NewVSplit(list, detail)
+	content.Offset = 0.5
+
+	return container.NewBorder(
+		/* top		*/ nil,
+		/* bottom	*/ x.makeHistoryOperation(),
+		/* left		*/ nil,
+		/* right	*/ nil,
+		/* center	*/ content,
+	)
+}
+
+func (x *MainWindow) makeHistoryOperation() fyne.CanvasObject {
+	// 将选中的记录的配置应用到 <ClientBox> 上。返回 false 表示没有选中记录。
+	load := func() bool {
+		entry, ok := x.selectedHistory()
+		if !ok {
+			return false
+		}
+
+		x.clientBoxData.client.SetConfig(x.restoreSecrets(entry.Config))
+		return true
+	}
+
+	btnLoad := widget.NewButton("LOAD", func() {
+		x.confirmDiscard(func() { load() }, nil)
+	})
+
+	btnReplay := widget.NewButton("REPLAY", func() {
+		submitter, ok := x.clientBoxData.client.(Submitter)
+		if !ok {
+			x.showError(fmt.Errorf("the client does not support replaying"))
+			return
+		}
+
+		x.confirmDiscard(func() {
+			if load() {
+				submitter.Submit()
+			}
+		}, nil)
+	})
+
+	btnSaveAs := widget.NewButton("SAVE AS", func() {
+		entry, ok := x.selectedHistory()
+		if !ok {
+			return
+		}
+
+		x.showConfigKeyDialog("Save as config", "SAVE", "", func(key string) {
+			x.confirmOverwrite(key, func(overwrite bool) {
+				c := x.clientBoxData.client
+				err := x.configManager.SaveAs(c.Name(), key, x.restoreSecrets(entry.Config), overwrite)
+				if err != nil {
+					x.showError(err)
+					return
+				}
+
+				x.reloadConfig(c.Name())
+				x.configAreaData.selectedKey.Set(key)
+			})
+		})
+	})
+
+	btnClear := widget.NewButton("CLEAR", func() {
+		callback := func(ok bool) {
+			if !ok {
+				return
+			}
+
+			c := x.clientBoxData.client
+			err := x.configManager.ClearHistory(c.Name())
+			if err != nil {
+				x.showError(err)
+				return
+			}
+			x.reloadHistory(c.Name())
+		}
+		dialog.ShowConfirm("Confirm clearing", "Clear all history of the client?", callback, x.win)
+	})
+
+	return container.NewVBox(
+		widget.NewSeparator(),
+		container.NewGridWithColumns(2, btnLoad, btnReplay, btnSaveAs, btnClear),
+	)
+}
+
+// 历史记录中的敏感字段已被替换为 [RedactedText] （见 [SecretMarker] ），使用界面上当前的值代替。
+func (x *MainWindow) restoreSecrets(config map[string]any) map[string]any {
+	c := x.clientBoxData.client
+	marker, ok := c.(SecretMarker)
+	if !ok {
+		return config
+	}
+	return RestoreRedacted(config, c.GetConfig(), marker.SecretKeys())
+}
+
+func (x *MainWindow) selectedHistory() (HistoryEntry, bool) {
+	x.historyData.mu.Lock()
+	idx := x.historyData.selected
+	x.historyData.mu.Unlock()
+	return x.historyData.entry(idx)
+}
+
+// 接收 [HistoryReporter] 报告的历史记录，可能在非 UI 线程上被调用。
+func (x *MainWindow) onHistory(clientName string, entry HistoryEntry) {
+	err := x.configManager.AppendHistory(clientName, entry)
+	if err != nil {
+		x.showError(err)
+		return
+	}
+
+	if c := x.currentClient(); c != nil && c.Name() == clientName {
+		x.reloadHistory(clientName)
+	}
+}
+
+// 重新读取 <HistoryList> ，可在任意 goroutine 中调用。
+func (x *MainWindow) reloadHistory(clientName string) {
+	entries, err := x.configManager.LoadHistory(clientName)
+	if err != nil {
+		x.showError(err)
+	}
+
+	// 刷新列表时会回调读取 entries ，不能在持有锁时刷新。
+	x.historyData.mu.Lock()
+	x.historyData.entries = entries
+	x.historyData.selected = -1
+	x.historyData.mu.Unlock()
+
+	x.historyData.detail.Set("")
+	x.historyData.list.UnselectAll()
+	x.historyData.list.Refresh()
+}
+
+// 列表中一条记录的简述，形如： 15:04:05 200 12ms http://temp.org/api
+func (x *MainWindow) describeHistory(entry HistoryEntry) string {
+	status := "ERR"
+	if entry.StatusCode != 0 {
+		status = fmt.Sprint(entry.StatusCode)
+	}
+
+	return fmt.Sprintf("%s %s %dms %s",
+		entry.Time.Format("15:04:05"), status, entry.Duration.Milliseconds(), entry.URL)
+}
+
+func (x *MainWindow) formatHistoryDetail(entry HistoryEntry) string {
+	b := new(strings.Builder)
+	fmt.Fprintf(b, "Time: %s\n", entry.Time.Format("2006-01-02 15:04:05.000"))
+	fmt.Fprintf(b, "URL: %s\n", entry.URL)
+	fmt.Fprintf(b, "Status: %d\n", entry.StatusCode)
+	fmt.Fprintf(b, "Duration: %s\n", entry.Duration)
+	if entry.Error != "" {
+		fmt.Fprintf(b, "Error: %s\n", entry.Error)
+	}
+	fmt.Fprintf(b, "\n----- Param -----\n%s\n", entry.Param)
+	fmt.Fprintf(b, "\n----- Response -----\n%s\n", entry.Response)
+	return b.String()
+}
@@ -0,0 +1,132 @@
+package client
+
+import (
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+)
+
+/*
+<ConfigList> 上方的搜索框，见 [ConfigManager.Search] 。
+
+输入内容后， <ConfigList> 只展示匹配的配置（及其所在的目录），目录全部展开。
+勾选 Contents 时，一并匹配配置的内容，如 URL 、参数。
+在搜索框中，上下方向键按匹配程度依次选中匹配的配置，回车选中当前（默认为第一个）匹配的配置， Esc 清空搜索框。
+*/
+
+// 搜索框的内容。
+type searchQuery struct {
+	text     string
+	contents bool // 是否搜索配置的内容。
+}
+
+// 可响应方向键等按键的输入框。
+type searchEntry struct {
+	widget.Entry
+	onUp     func()
+	onDown   func()
+	onEscape func()
+}
+
+func newSearchEntry() *searchEntry {
+	entry := &searchEntry{}
+	entry.ExtendBaseWidget(entry)
+	return entry
+}
+
+func (x *searchEntry) TypedKey(key *fyne.KeyEvent) {
+	switch key.Name {
+	case fyne.KeyUp:
+		x.onUp()
+	case fyne.KeyDown:
+		x.onDown()
+	case fyne.KeyEscape:
+		x.onEscape()
+	default:
+		x.Entry.TypedKey(key)
+	}
+}
+
+func (x *MainWindow) makeConfigSearch() fyne.CanvasObject {
+	search := newSearchEntry()
+	search.SetPlaceHolder("Filter configs")
+	x.configAreaData.search = search
+
+	contentsCheck := widget.NewCheck("Contents", nil)
+	x.configAreaData.searchContents = contentsCheck
+
+	refresh := func() {
+		x.stateMu.Lock()
+		x.configAreaData.query = searchQuery{text: search.Text, contents: contentsCheck.Checked}
+		x.stateMu.Unlock()
+
+		if c := x.clientBoxData.client; c != nil {
+			x.reloadConfig(c.Name())
+		}
+	}
+	search.OnChanged = func(string) { refresh() }
+	contentsCheck.OnChanged = func(bool) { refresh() }
+
+	search.onDown = func() { x.selectMatch(1) }
+	search.onUp = func() { x.selectMatch(-1) }
+	search.onEscape = func() { search.SetText("") }
+	search.OnSubmitted = func(string) { x.selectMatch(0) }
+
+	return container.NewBorder(nil, nil, nil, contentsCheck, search)
+}
+
+// 返回搜索框的内容，可在任意 goroutine 中调用。
+func (x *MainWindow) searchQuery() searchQuery {
+	x.stateMu.Lock()
+	defer x.stateMu.Unlock()
+	return x.configAreaData.query
+}
+
+// 是否正在搜索。
+func (x *MainWindow) searching() bool {
+	return strings.TrimSpace(x.searchQuery().text) != ""
+}
+
+// 按搜索的结果刷新 <ConfigList> 。
+func (x *MainWindow) reloadSearch(clientName string) {
+	query := x.searchQuery()
+	keys, err := x.configManager.Search(clientName, query.text, query.contents)
+	if err != nil {
+		x.showError(err)
+	}
+
+	tree := newConfigTree(keys, nil)
+	x.stateMu.Lock()
+	x.configAreaData.matches = keys
+	x.configAreaData.matchIndex = -1
+	x.configAreaData.configTree = tree
+	x.stateMu.Unlock()
+
+	x.configAreaData.tree.Refresh()
+	x.configAreaData.tree.OpenAllBranches()
+}
+
+// 选中搜索结果中的配置， step 为 1 或 -1 时选中下一个或上一个，为 0 时选中当前的，尚未选中时选中第一个。
+func (x *MainWindow) selectMatch(step int) {
+	x.stateMu.Lock()
+	matches := x.configAreaData.matches
+	if len(matches) == 0 {
+		x.stateMu.Unlock()
+		return
+	}
+
+	i := x.configAreaData.matchIndex
+	switch {
+	case i < 0:
+		i = 0
+	default:
+		i = (i + step + len(matches)) % len(matches)
+	}
+	x.configAreaData.matchIndex = i
+	x.stateMu.Unlock()
+
+	x.configAreaData.tree.ScrollTo(matches[i])
+	x.configAreaData.tree.Select(matches[i])
+}
@@ -0,0 +1,181 @@
+package client
+
+import (
+	"fmt"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+/*
+敏感字段的加密存储（见 secrets.go ）在主窗口上的操作：
+  - 启动时，若已启用加密存储，自动解锁；口令模式下弹出对话框要求输入口令。
+  - Settings 菜单下的 Secrets... ：启用加密存储、解锁，以及将已有配置中的明文原地加密。
+*/
+
+// 启动时解锁加密存储。未启用时不做任何事。
+func (x *MainWindow) unlockSecretsOnStart() {
+	mode, err := x.configManager.SecretMode()
+	if err != nil {
+		x.showError(err)
+		return
+	}
+
+	switch mode {
+	case SecretModeNone:
+	case SecretModePassphrase:
+		x.showPassphrasePrompt(nil)
+	default:
+		if err := x.configManager.UnlockSecrets(""); err != nil {
+			x.showError(err)
+		}
+	}
+}
+
+// 要求输入口令以解锁加密存储，口令错误时重新要求输入。解锁成功后调用 onUnlocked （可以为 nil ）。
+func (x *MainWindow) showPassphrasePrompt(onUnlocked func()) {
+	passphraseInput := widget.NewPasswordEntry()
+	items := []*widget.FormItem{
+		{Text: "Passphrase", Widget: passphraseInput, HintText: "configs with secrets cannot be used until unlocked"},
+	}
+
+	callback := func(ok bool) {
+		if !ok {
+			return
+		}
+
+		err := x.configManager.UnlockSecrets(passphraseInput.Text)
+		if err != nil {
+			dialog.ShowError(err, x.win)
+			x.showPassphrasePrompt(onUnlocked)
+			return
+		}
+
+		if onUnlocked != nil {
+			onUnlocked()
+		}
+	}
+
+	d := dialog.NewForm("Unlock secrets", "UNLOCK", "LATER", items, callback, x.win)
+	d.Resize(fyne.NewSize(x.width/3, 0))
+	d.Show()
+}
+
+// 查看加密存储的状态；未启用时可启用，已启用时可解锁和迁移已有的明文配置。
+func (x *MainWindow) showSecretsDialog() {
+	mode, err := x.configManager.SecretMode()
+	if err != nil {
+		x.showError(err)
+		return
+	}
+
+	if mode == SecretModeNone {
+		x.showEnableSecretsDialog()
+		return
+	}
+
+	locked, err := x.configManager.SecretsLocked()
+	if err != nil {
+		x.showError(err)
+		return
+	}
+
+	state := "unlocked"
+	if locked {
+		state = "locked"
+	}
+
+	var d dialog.Dialog
+	btnUnlock := widget.NewButton("UNLOCK", func() {
+		d.Hide()
+		if mode == SecretModePassphrase {
+			x.showPassphrasePrompt(x.showSecretsDialog)
+			return
+		}
+
+		if err := x.configManager.UnlockSecrets(""); err != nil {
+			x.showError(err)
+			return
+		}
+		x.showSecretsDialog()
+	})
+
+	btnMigrate := widget.NewButton("ENCRYPT EXISTING CONFIGS", func() {
+		d.Hide()
+		x.migrateSecrets()
+	})
+
+	if locked {
+		btnMigrate.Disable()
+	} else {
+		btnUnlock.Disable()
+	}
+
+	content := container.NewVBox(
+		widget.NewLabel(fmt.Sprintf("Mode: %s, %s", mode, state)),
+		btnUnlock,
+		btnMigrate,
+	)
+	d = dialog.NewCustom("Secrets", "CLOSE", content, x.win)
+	d.Show()
+}
+
+func (x *MainWindow) showEnableSecretsDialog() {
+	modeSelect := widget.NewSelect([]string{SecretModeKeyring, SecretModePassphrase, SecretModeFile}, nil)
+	modeSelect.SetSelected(SecretModeKeyring)
+
+	passphraseInput := widget.NewPasswordEntry()
+	confirmInput := widget.NewPasswordEntry()
+
+	items := []*widget.FormItem{
+		{Text: "Mode", Widget: modeSelect, HintText: "keyring falls back to file if the OS keyring is not available"},
+		{Text: "Passphrase", Widget: passphraseInput, HintText: "only for the passphrase mode"},
+		{Text: "Confirm", Widget: confirmInput},
+	}
+
+	callback := func(ok bool) {
+		if !ok {
+			return
+		}
+
+		if modeSelect.Selected == SecretModePassphrase && passphraseInput.Text != confirmInput.Text {
+			dialog.ShowInformation("Error", "The passphrases do not match.", x.win)
+			return
+		}
+
+		actual, err := x.configManager.EnableSecrets(modeSelect.Selected, passphraseInput.Text)
+		if err != nil {
+			x.showError(err)
+			return
+		}
+
+		msg := fmt.Sprintf("The secret store is enabled with mode %s.\nEncrypt the secrets in existing configs now?", actual)
+		dialog.ShowConfirm("Secrets", msg, func(ok bool) {
+			if ok {
+				x.migrateSecrets()
+			}
+		}, x.win)
+	}
+
+	d := dialog.NewForm("Enable secret encryption", "ENABLE", "CANCEL", items, callback, x.win)
+	d.Resize(fyne.NewSize(x.width/2, 0))
+	d.Show()
+}
+
+// 将所有 Client 的已有配置中明文的敏感字段原地加密。
+func (x *MainWindow) migrateSecrets() {
+	lines := make([]string, 0, len(x.clients))
+	for _, c := range x.clients {
+		n, err := x.configManager.MigrateSecrets(c.Name())
+		if err != nil {
+			x.showError(err)
+			return
+		}
+		lines = append(lines, fmt.Sprintf("%s: %d config(s) encrypted", c.Name(), n))
+	}
+
+	dialog.ShowInformation("Secrets", strings.Join(lines, "\n"), x.win)
+}
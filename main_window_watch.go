@@ -0,0 +1,133 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+/*
+<ConfigList> 与磁盘上的配置保持同步：
+  - 通过 [ConfigManager.Watch] 监视配置目录，当前 Client 的配置或目录在程序外（如另一个窗口、命令行模式、同步工具）
+    发生变化时，刷新 <ConfigList> 。
+  - 当前载入的配置在磁盘上被修改时，若 <ClientBox> 上没有未保存的改动，直接重新载入；否则提示，可选择载入磁盘上的版本或保留当前的改动。
+  - 当前载入的配置被删除或移走时，保留 <ClientBox> 上的内容，视为尚未保存过的配置。
+*/
+
+// 磁盘上的配置变化的处理状态。
+type configWatchData struct {
+	mu       sync.Mutex
+	conflict dialog.Dialog // 正在展示的冲突提示，避免同一时间弹出多个。
+}
+
+// 在后台监视配置目录，返回用于停止监视的函数。无法监视时展示错误，程序仍可正常使用。
+func (x *MainWindow) watchConfigDir() (stop func()) {
+	ctx, cancel := context.WithCancel(context.Background())
+	ch, err := x.configManager.Watch(ctx)
+	if err != nil {
+		cancel()
+		x.showError(err)
+		return func() {}
+	}
+
+	go func() {
+		for change := range ch {
+			x.onConfigChanged(change)
+		}
+	}()
+
+	return cancel
+}
+
+// 处理磁盘上的配置变化，在监视配置目录的 goroutine 中调用。
+func (x *MainWindow) onConfigChanged(change ConfigChange) {
+	c := x.currentClient()
+	if c == nil || change.Client != c.Name() {
+		return
+	}
+
+	x.reloadConfig(c.Name())
+
+	// 目录的变化可能使当前载入的配置被移走，也需要检查。
+	key := x.changes.loadedKey()
+	if key == "" || (change.Key != "" && change.Key != key) {
+		return
+	}
+
+	conf, err := x.configManager.Load(c.Name(), key)
+	switch {
+	case errors.Is(err, ErrConfigNotFound):
+		x.changes.setKey("")
+		x.configAreaData.tree.Refresh()
+		return
+
+	case err != nil:
+		// 文件可能正被其他程序写入，内容暂时不完整；写入完成后还会收到变化，这里不打扰用户。
+		return
+
+	case x.changes.isSaved(conf):
+		// 内容没有变化，如由当前程序自身的保存操作引起。
+		return
+	}
+
+	if dirty, _ := x.changes.check(); !dirty {
+		x.applyDiskConfig(key, conf)
+		return
+	}
+
+	x.showDiskConflict(key)
+}
+
+// 将从磁盘读取的配置应用到 <ClientBox> 上，作为当前载入的配置。
+func (x *MainWindow) applyDiskConfig(key string, conf map[string]any) {
+	x.currentClient().SetConfig(conf)
+	x.setLoadedKey(key)
+}
+
+// 当前载入的配置在磁盘上被修改，而 <ClientBox> 上有未保存的改动时，提示载入磁盘上的版本或保留当前的改动。
+func (x *MainWindow) showDiskConflict(key string) {
+	x.watchData.mu.Lock()
+	defer x.watchData.mu.Unlock()
+
+	if x.watchData.conflict != nil {
+		return
+	}
+
+	var d dialog.Dialog
+	btnReload := widget.NewButton("RELOAD", func() {
+		d.Hide()
+
+		// 提示展示期间，配置可能已被切换。
+		if x.changes.loadedKey() != key {
+			return
+		}
+
+		conf, err := x.configManager.Load(x.clientBoxData.client.Name(), key)
+		if err != nil {
+			x.showError(err)
+			return
+		}
+		x.applyDiskConfig(key, conf)
+	})
+
+	msg := fmt.Sprintf("Config >> %s << has been changed on disk, but the current config has unsaved changes.\n"+
+		"RELOAD discards the changes, KEEP MINE overwrites the file on the next save.", key)
+	content := container.NewVBox(
+		widget.NewLabel(msg),
+		btnReload,
+	)
+
+	d = dialog.NewCustom("Config changed on disk", "KEEP MINE", content, x.win)
+	d.SetOnClosed(func() {
+		x.watchData.mu.Lock()
+		defer x.watchData.mu.Unlock()
+		x.watchData.conflict = nil
+	})
+	x.watchData.conflict = d
+	d.Show()
+}
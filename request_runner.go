@@ -0,0 +1,118 @@
+package client
+
+import (
+	"context"
+	"sync"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/data/binding"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// 请求被取消时，展示在结果区域的文本。
+const CancelledText = "cancelled"
+
+// 管理界面上异步执行的请求：同一时间只执行一个请求，正在执行的请求可以被取消。
+// 供各 [Client] 的实现共用。
+type RequestRunner struct {
+	mu      sync.Mutex
+	cancel  context.CancelFunc // 当前请求的取消函数，没有正在执行的请求时为 nil 。
+	running binding.Bool       // 是否有正在执行的请求。
+
+	buttonsMu sync.Mutex
+	btnSubmit *widget.Button // 最近一次 Buttons() 中的按钮，尚未展示时为 nil 。
+	btnCancel *widget.Button
+}
+
+// 创建一个 [*RequestRunner] 。
+func NewRequestRunner() *RequestRunner {
+	x := &RequestRunner{
+		running: binding.NewBool(),
+	}
+
+	// 只注册一次，更新最近一次 Buttons() 中的按钮，多次调用 Buttons() 时不会累积。
+	x.running.AddListener(binding.NewDataListener(x.updateButtons))
+	return x
+}
+
+// 在新的 goroutine 中执行 fn ，传入的 ctx 在调用 [RequestRunner.Cancel] 时被取消。
+// 若已有正在执行的请求，则不执行 fn 并返回 false 。
+func (x *RequestRunner) Run(fn func(ctx context.Context)) bool {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	if x.cancel != nil {
+		return false
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	x.cancel = cancel
+	x.running.Set(true)
+
+	go func() {
+		defer func() {
+			x.mu.Lock()
+			defer x.mu.Unlock()
+
+			cancel()
+			x.cancel = nil
+			x.running.Set(false)
+		}()
+
+		fn(ctx)
+	}()
+
+	return true
+}
+
+// 取消正在执行的请求。没有正在执行的请求时，操作被忽略。
+func (x *RequestRunner) Cancel() {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	if x.cancel != nil {
+		x.cancel()
+	}
+}
+
+// 返回是否有正在执行的请求的绑定，可用于控制界面元素的状态。
+func (x *RequestRunner) Running() binding.Bool {
+	return x.running
+}
+
+// 返回包含 Submit 和 Cancel 按钮的界面。有正在执行的请求时 Submit 不可用，反之 Cancel 不可用。
+func (x *RequestRunner) Buttons(onSubmit func()) fyne.CanvasObject {
+	btnSubmit := widget.NewButtonWithIcon("Submit", theme.ConfirmIcon(), onSubmit)
+	btnSubmit.Importance = widget.HighImportance
+
+	btnCancel := widget.NewButtonWithIcon("Cancel", theme.CancelIcon(), x.Cancel)
+
+	x.buttonsMu.Lock()
+	x.btnSubmit = btnSubmit
+	x.btnCancel = btnCancel
+	x.buttonsMu.Unlock()
+	x.updateButtons()
+
+	return container.NewGridWithColumns(2, btnSubmit, btnCancel)
+}
+
+// 按是否有正在执行的请求，设置按钮是否可用。
+func (x *RequestRunner) updateButtons() {
+	x.buttonsMu.Lock()
+	defer x.buttonsMu.Unlock()
+
+	if x.btnSubmit == nil {
+		return
+	}
+
+	running, _ := x.running.Get()
+	if running {
+		x.btnSubmit.Disable()
+		x.btnCancel.Enable()
+	} else {
+		x.btnSubmit.Enable()
+		x.btnCancel.Disable()
+	}
+}
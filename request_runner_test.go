@@ -0,0 +1,80 @@
+package client
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/widget"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestRunner(t *testing.T) {
+	r := require.New(t)
+	runner := NewRequestRunner()
+
+	started := make(chan struct{})
+	done := make(chan error)
+	ok := runner.Run(func(ctx context.Context) {
+		close(started)
+		<-ctx.Done()
+		done <- ctx.Err()
+	})
+	r.True(ok)
+	<-started
+
+	running, _ := runner.Running().Get()
+	r.True(running)
+
+	// 正在执行时，新的请求被忽略。
+	r.False(runner.Run(func(ctx context.Context) {
+		t.Error("should not run")
+	}))
+
+	runner.Cancel()
+	select {
+	case err := <-done:
+		r.ErrorIs(err, context.Canceled)
+	case <-time.After(time.Second):
+		t.Fatal("not cancelled")
+	}
+
+	r.Eventually(func() bool {
+		running, _ := runner.Running().Get()
+		return !running
+	}, time.Second, 10*time.Millisecond)
+
+	// 上一个请求结束后可以再次执行。
+	finished := make(chan struct{})
+	r.True(runner.Run(func(ctx context.Context) {
+		close(finished)
+	}))
+	<-finished
+
+	runner.Cancel() // 没有正在执行的请求时不做任何事。
+}
+
+func TestRequestRunner_Buttons(t *testing.T) {
+	r := require.New(t)
+	runner := NewRequestRunner()
+
+	buttons := func(box fyne.CanvasObject) (submit, cancel *widget.Button) {
+		objects := box.(*fyne.Container).Objects
+		return objects[0].(*widget.Button), objects[1].(*widget.Button)
+	}
+
+	// 多次创建按钮时，只更新最近一次的。
+	oldSubmit, _ := buttons(runner.Buttons(func() {}))
+	submit, cancel := buttons(runner.Buttons(func() {}))
+	r.False(submit.Disabled())
+	r.True(cancel.Disabled())
+
+	stop := make(chan struct{})
+	r.True(runner.Run(func(ctx context.Context) { <-stop }))
+	r.Eventually(func() bool { return submit.Disabled() && !cancel.Disabled() }, time.Second, 10*time.Millisecond)
+	r.False(oldSubmit.Disabled())
+
+	close(stop)
+	r.Eventually(func() bool { return !submit.Disabled() && cancel.Disabled() }, time.Second, 10*time.Millisecond)
+}
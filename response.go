@@ -0,0 +1,144 @@
+package client
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptrace"
+	"sync"
+	"time"
+)
+
+// 表示一次 HTTP 请求的执行结果，供各 [Client] 的实现共用。
+type Response struct {
+	Proto      string         // 协议，如“HTTP/1.1”。
+	Status     string         // 状态行，如“200 OK”。
+	StatusCode int            // 状态码。
+	Header     http.Header    // 响应头。
+	Body       []byte         // 响应的 body 。
+	Duration   time.Duration  // 从发出请求到读取完 body 的耗时。
+	Timing     ResponseTiming // 各阶段的耗时。
+}
+
+// 请求各阶段的耗时，通过 [httptrace] 获取。
+// 复用已有连接时，没有 DNS 、建立连接和 TLS 握手的过程，对应的值为 0 。
+type ResponseTiming struct {
+	DNS     time.Duration // DNS 解析。
+	Connect time.Duration // 建立 TCP 连接。
+	TLS     time.Duration // TLS 握手。
+	TTFB    time.Duration // 从发出请求到收到响应的第一个字节（time to first byte）。
+}
+
+// 返回格式化后的 body 。若 body 是 JSON ，则缩进后输出；否则原样输出。
+func (x *Response) FormatBody() string {
+	res, _ := IndentJson(x.Body)
+	return res
+}
+
+// 状态码是否为 2xx 。
+func (x *Response) IsSuccess() bool {
+	return x.StatusCode >= 200 && x.StatusCode <= 299
+}
+
+// 返回 body 的字节数。
+func (x *Response) Size() int {
+	return len(x.Body)
+}
+
+// 发送请求并读取完整的响应， body 读取完毕后会被关闭。各阶段的耗时记录在 [Response.Timing] 。
+// 若 httpClient 为 nil ，使用 [http.DefaultClient] 。
+// 仅当请求无法发出或响应无法读取时返回 error ，非 2xx 的响应不被视为错误。
+func SendRequest(httpClient *http.Client, request *http.Request) (*Response, error) {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	trace := &timingTrace{start: time.Now()}
+	request = request.WithContext(httptrace.WithClientTrace(request.Context(), trace.clientTrace()))
+
+	response, err := httpClient.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	responseBody, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &Response{
+		Proto:      response.Proto,
+		Status:     response.Status,
+		StatusCode: response.StatusCode,
+		Header:     response.Header,
+		Body:       responseBody,
+		Duration:   time.Since(trace.start),
+		Timing:     trace.timing(),
+	}
+	return res, nil
+}
+
+// 记录 [ResponseTiming] 的 [httptrace.ClientTrace] 的回调可能在不同的 goroutine 上执行，需加锁。
+type timingTrace struct {
+	mu                  sync.Mutex
+	start               time.Time
+	dnsStart, dnsDone   time.Time
+	connStart, connDone time.Time
+	tlsStart, tlsDone   time.Time
+	firstByte           time.Time
+}
+
+func (x *timingTrace) clientTrace() *httptrace.ClientTrace {
+	set := func(t *time.Time) {
+		x.mu.Lock()
+		defer x.mu.Unlock()
+
+		// 可能有多次连接尝试，只记录第一次的开始时间和最后一次的结束时间。
+		if t.IsZero() || t == &x.dnsDone || t == &x.connDone || t == &x.tlsDone {
+			*t = time.Now()
+		}
+	}
+
+	return &httptrace.ClientTrace{
+		DNSStart:             func(httptrace.DNSStartInfo) { set(&x.dnsStart) },
+		DNSDone:              func(httptrace.DNSDoneInfo) { set(&x.dnsDone) },
+		ConnectStart:         func(string, string) { set(&x.connStart) },
+		ConnectDone:          func(string, string, error) { set(&x.connDone) },
+		TLSHandshakeStart:    func() { set(&x.tlsStart) },
+		TLSHandshakeDone:     func(tls.ConnectionState, error) { set(&x.tlsDone) },
+		GotFirstResponseByte: func() { set(&x.firstByte) },
+	}
+}
+
+func (x *timingTrace) timing() ResponseTiming {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	since := func(start, end time.Time) time.Duration {
+		if start.IsZero() || end.IsZero() {
+			return 0
+		}
+		return end.Sub(start)
+	}
+
+	return ResponseTiming{
+		DNS:     since(x.dnsStart, x.dnsDone),
+		Connect: since(x.connStart, x.connDone),
+		TLS:     since(x.tlsStart, x.tlsDone),
+		TTFB:    since(x.start, x.firstByte),
+	}
+}
+
+// 尝试格式化 JSON 。若给定过的不是合法的 JSON ，返回原值的字符串形式 + ok=false。
+func IndentJson(v []byte) (res string, ok bool) {
+	const ident = "    "
+	buf := new(bytes.Buffer)
+	err := json.Indent(buf, v, "", ident)
+	if err != nil {
+		return string(v), false
+	}
+	return buf.String(), true
+}
@@ -0,0 +1,64 @@
+package client
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSendRequest(t *testing.T) {
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Test", "1")
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"a":1}`))
+	}))
+	defer ts.Close()
+
+	r := require.New(t)
+	request, err := http.NewRequest(http.MethodGet, ts.URL, nil)
+	r.NoError(err)
+
+	res, err := SendRequest(ts.Client(), request)
+	r.NoError(err)
+	r.Equal("HTTP/1.1", res.Proto)
+	r.Equal("404 Not Found", res.Status)
+	r.Equal(404, res.StatusCode)
+	r.False(res.IsSuccess())
+	r.Equal("1", res.Header.Get("X-Test"))
+	r.Equal(7, res.Size())
+	r.Equal("{\n    \"a\": 1\n}", res.FormatBody())
+
+	r.Greater(res.Timing.Connect, time.Duration(0))
+	r.Greater(res.Timing.TLS, time.Duration(0))
+	r.Greater(res.Timing.TTFB, time.Duration(0))
+	r.GreaterOrEqual(res.Duration, res.Timing.TTFB)
+}
+
+func TestFormatResponse(t *testing.T) {
+	res := &Response{
+		Proto:  "HTTP/1.1",
+		Status: "200 OK",
+		Header: http.Header{"B": {"2", "3"}, "A": {"1"}},
+		Body:   []byte("body"),
+		Timing: ResponseTiming{
+			DNS:     1 * time.Millisecond,
+			Connect: 2 * time.Millisecond,
+			TTFB:    3 * time.Millisecond,
+		},
+		Duration: 4 * time.Millisecond,
+	}
+
+	require.Equal(t, "A: 1\nB: 2\nB: 3\n", FormatHeaders(res))
+	require.Equal(t, `HTTP/1.1 200 OK
+Size: 4 bytes
+
+DNS lookup:         1ms
+TCP connect:        2ms
+TLS handshake:      0s
+Time to first byte: 3ms
+Total:              4ms
+`, FormatResponseInfo(res))
+}
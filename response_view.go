@@ -0,0 +1,232 @@
+package client
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/data/binding"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// 展示 [Response] 的界面组件，供各 [Client] 的实现共用。分为以下标签页：
+//   - Body 格式化后的 body 。
+//   - Data 若 body 是 go-webapi 的回执信封（见 [ParseApiEnvelope] ），展示 Code 、 Message ，
+//     并以树形结构展示 Data ，可复制节点的路径和值。
+//   - Headers 响应头。
+//   - Info 状态行、大小和各阶段耗时。
+type ResponseView struct {
+	body    binding.String
+	headers binding.String
+	info    binding.String
+
+	// Data 标签页的数据，在 Box() 中初始化。
+	dataView struct {
+		tabs     *container.AppTabs
+		status   *widget.Label
+		icon     *widget.Icon
+		tree     *widget.Tree
+		data     *jsonTree // 当前展示的 Data ，没有信封时为 nil 。
+		selected string    // 选中的节点的路径。
+	}
+}
+
+// 创建一个 [*ResponseView] 。
+func NewResponseView() *ResponseView {
+	return &ResponseView{
+		body:    binding.NewString(),
+		headers: binding.NewString(),
+		info:    binding.NewString(),
+	}
+}
+
+// 读取当前界面的元素。
+func (x *ResponseView) Box() fyne.CanvasObject {
+	newTab := func(title string, data binding.String) *container.TabItem {
+		entry := widget.NewMultiLineEntry()
+		entry.Bind(data)
+		return container.NewTabItem(title, container.NewVScroll(entry))
+	}
+
+	tabs := container.NewAppTabs(
+		newTab("Body", x.body),
+		container.NewTabItem("Data", x.makeDataTab()),
+		newTab("Headers", x.headers),
+		newTab("Info", x.info),
+	)
+	x.dataView.tabs = tabs
+	return tabs
+}
+
+func (x *ResponseView) makeDataTab() fyne.CanvasObject {
+	x.dataView.status = widget.NewLabel("")
+	x.dataView.status.Wrapping = fyne.TextWrapWord
+	x.dataView.icon = widget.NewIcon(nil)
+
+	tree := widget.NewTree(
+		func(id widget.TreeNodeID) []widget.TreeNodeID {
+			if x.dataView.data == nil {
+				return nil
+			}
+			return x.dataView.data.children[id]
+		},
+		func(id widget.TreeNodeID) bool {
+			return x.dataView.data != nil && x.dataView.data.isBranch(id)
+		},
+		func(branch bool) fyne.CanvasObject {
+			return widget.NewLabel("")
+		},
+		func(id widget.TreeNodeID, branch bool, o fyne.CanvasObject) {
+			if x.dataView.data != nil {
+				o.(*widget.Label).SetText(x.dataView.data.labels[id])
+			}
+		},
+	)
+	tree.OnSelected = func(id widget.TreeNodeID) {
+		x.dataView.selected = id
+	}
+	x.dataView.tree = tree
+
+	copyToClipboard := func(get func(path string) string) func() {
+		return func() {
+			if x.dataView.data == nil || x.dataView.selected == "" {
+				return
+			}
+
+			if clipboard := clipboardFor(tree); clipboard != nil {
+				clipboard.SetContent(get(x.dataView.selected))
+			}
+		}
+	}
+
+	btnCopyPath := widget.NewButtonWithIcon("Copy path", theme.ContentCopyIcon(), copyToClipboard(func(path string) string {
+		return path
+	}))
+	btnCopyValue := widget.NewButtonWithIcon("Copy value", theme.ContentCopyIcon(), copyToClipboard(func(path string) string {
+		return x.dataView.data.valueText(path)
+	}))
+
+	return container.NewBorder(
+		/* top		*/ container.NewBorder(nil, nil, x.dataView.icon, nil, x.dataView.status),
+		/* bottom	*/ container.NewGridWithColumns(2, btnCopyPath, btnCopyValue),
+		/* left		*/ nil,
+		/* right	*/ nil,
+		/* center	*/ tree,
+	)
+}
+
+// 在 Body 标签页展示一段文本，如请求的进度或错误信息，其余标签页被清空。
+func (x *ResponseView) SetText(text string) {
+	x.body.Set(text)
+	x.headers.Set("")
+	x.info.Set("")
+	x.setEnvelope(nil)
+}
+
+// 展示给定的响应。若 body 是 go-webapi 的回执信封，则切换到 Data 标签页。
+func (x *ResponseView) SetResponse(res *Response) {
+	x.body.Set(res.FormatBody())
+	x.headers.Set(FormatHeaders(res))
+	x.info.Set(FormatResponseInfo(res))
+
+	env, _ := ParseApiEnvelope(res.Body)
+	x.setEnvelope(env)
+}
+
+func (x *ResponseView) setEnvelope(env *ApiEnvelope) {
+	// Box() 尚未被调用时，没有界面需要更新。
+	if x.dataView.tabs == nil {
+		return
+	}
+
+	x.dataView.selected = ""
+	x.dataView.tree.UnselectAll()
+
+	switch {
+	case env == nil:
+		x.dataView.data = nil
+		x.dataView.icon.SetResource(nil)
+		x.dataView.status.SetText("The response is not a go-webapi ApiResponse.")
+
+	case env.IsSuccess():
+		x.dataView.data = newJsonTree("Data", env.Data)
+		x.dataView.icon.SetResource(theme.ConfirmIcon())
+		x.dataView.status.SetText("Code: 0")
+
+	default:
+		x.dataView.data = newJsonTree("Data", env.Data)
+		x.dataView.icon.SetResource(theme.ErrorIcon())
+		x.dataView.status.SetText(fmt.Sprintf("ERROR Code: %d, Message: %s", env.Code, env.Message))
+	}
+
+	x.dataView.tree.Refresh()
+	if env != nil {
+		x.dataView.tree.OpenBranch("Data")
+		x.dataView.tabs.SelectIndex(1)
+	}
+}
+
+// 返回当前 Body 标签页的文本。
+func (x *ResponseView) Text() string {
+	s, _ := x.body.Get()
+	return s
+}
+
+// 将响应头格式化为每行一个“Name: value”的形式，按名称排序。
+func FormatHeaders(res *Response) string {
+	names := make([]string, 0, len(res.Header))
+	for name := range res.Header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	b := new(strings.Builder)
+	for _, name := range names {
+		for _, v := range res.Header[name] {
+			fmt.Fprintf(b, "%s: %s\n", name, v)
+		}
+	}
+	return b.String()
+}
+
+// 格式化响应的状态行、大小和各阶段的耗时。
+func FormatResponseInfo(res *Response) string {
+	b := new(strings.Builder)
+	fmt.Fprintf(b, "%s %s\n", res.Proto, res.Status)
+	fmt.Fprintf(b, "Size: %d bytes\n", res.Size())
+	b.WriteRune('\n')
+	fmt.Fprintf(b, "DNS lookup:         %s\n", res.Timing.DNS)
+	fmt.Fprintf(b, "TCP connect:        %s\n", res.Timing.Connect)
+	fmt.Fprintf(b, "TLS handshake:      %s\n", res.Timing.TLS)
+	fmt.Fprintf(b, "Time to first byte: %s\n", res.Timing.TTFB)
+	fmt.Fprintf(b, "Total:              %s\n", res.Duration)
+	return b.String()
+}
+
+// 返回 obj 所在窗口的剪贴板。 obj 尚未展示时返回 nil 。
+func clipboardFor(obj fyne.CanvasObject) fyne.Clipboard {
+	w := windowFor(obj)
+	if w == nil {
+		return nil
+	}
+	return w.Clipboard()
+}
+
+// 返回 obj 所在的窗口，用于展示对话框等。 obj 尚未展示时返回 nil 。
+func windowFor(obj fyne.CanvasObject) fyne.Window {
+	app := fyne.CurrentApp()
+	if app == nil {
+		return nil
+	}
+
+	canvas := app.Driver().CanvasForObject(obj)
+	for _, w := range app.Driver().AllWindows() {
+		if w.Canvas() == canvas {
+			return w
+		}
+	}
+	return nil
+}
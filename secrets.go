@@ -0,0 +1,517 @@
+package client
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+/*
+敏感字段的加密存储。
+
+启用后，各 Client 通过 [SecretMarker] 标记的字段在 [ConfigManager.Save] 时以 AES-256-GCM 加密，
+保存为“enc:v1:<base64>”形式的字符串， [ConfigManager.Load] 时自动解密。
+未加密的值原样读取，因此旧的明文配置仍可使用，并可通过 [ConfigManager.MigrateSecrets] 原地加密。
+
+主密钥的来源见 SecretModeXxx ，设置保存在根目录下的 secrets.json 。
+*/
+
+// 加密存储的模式，即主密钥的来源。
+const (
+	SecretModeNone       = ""           // 未启用加密存储。
+	SecretModePassphrase = "passphrase" // 主密钥由口令派生（ PBKDF2-SHA256 ），每次启动时需输入口令。
+	SecretModeKeyring    = "keyring"    // 随机生成的主密钥保存在操作系统的密钥环中。
+	SecretModeFile       = "file"       // 随机生成的主密钥保存在根目录下权限为 0600 的文件中，用于没有密钥环的环境，如无图形界面的 Linux 。
+)
+
+// 命令行模式下，从此环境变量读取 [SecretModePassphrase] 的口令。
+const PassphraseEnvName = "WEBAPI_CLIENT_PASSPHRASE"
+
+const (
+	secretsFileName   = "secrets.json"
+	masterKeyFileName = ".master_key"
+	encryptedPrefix   = "enc:v1:"
+	secretCheckText   = "go-webapi-client"
+	pbkdf2Iterations  = 200000
+)
+
+// 加密存储的设置，保存在 secrets.json 。
+type secretSettings struct {
+	Mode  string // SecretModeXxx 之一。
+	Salt  string `json:",omitempty"` // 派生密钥使用的盐， base64 ，仅用于 SecretModePassphrase 。
+	Check string // 加密后的 secretCheckText ，用于校验主密钥是否正确。
+}
+
+// 返回加密存储的模式，未启用时返回 [SecretModeNone] 。
+func (x *ConfigManager) SecretMode() (string, error) {
+	settings, err := x.loadSecretSettings()
+	if err != nil {
+		return "", err
+	}
+	return settings.Mode, nil
+}
+
+// 是否已启用加密存储但尚未解锁。此时读写含有敏感字段的配置会返回 [ErrSecretsLocked] 类别的错误。
+func (x *ConfigManager) SecretsLocked() (bool, error) {
+	mode, err := x.SecretMode()
+	if err != nil {
+		return false, err
+	}
+
+	x.secrets.mu.RLock()
+	defer x.secrets.mu.RUnlock()
+	return mode != SecretModeNone && x.secrets.cipher == nil, nil
+}
+
+// 启用加密存储，启用后即处于解锁状态。 mode 为 [SecretModePassphrase] 时需给定 passphrase 。
+// mode 为 [SecretModeKeyring] 而密钥环不可用时，退回到 [SecretModeFile] 。返回实际使用的模式。
+//
+// 已有配置中的明文不会被自动加密，需调用 [ConfigManager.MigrateSecrets] 。
+func (x *ConfigManager) EnableSecrets(mode, passphrase string) (string, error) {
+	const op = "enable-secrets"
+
+	current, err := x.SecretMode()
+	if err != nil {
+		return "", err
+	}
+
+	if current != SecretModeNone {
+		return "", x.newError(op, secretsFileName, fmt.Errorf("the secret store is already enabled with mode %q", current))
+	}
+
+	settings := secretSettings{Mode: mode}
+	var key []byte
+	switch mode {
+	case SecretModePassphrase:
+		if passphrase == "" {
+			return "", x.newError(op, secretsFileName, errors.New("the passphrase cannot be empty"))
+		}
+
+		salt := make([]byte, 16)
+		if _, err := rand.Read(salt); err != nil {
+			return "", x.newError(op, secretsFileName, err)
+		}
+		settings.Salt = base64.StdEncoding.EncodeToString(salt)
+		key = pbkdf2Sha256([]byte(passphrase), salt, pbkdf2Iterations, 32)
+
+	case SecretModeKeyring, SecretModeFile:
+		key = make([]byte, 32)
+		if _, err := rand.Read(key); err != nil {
+			return "", x.newError(op, secretsFileName, err)
+		}
+
+		if mode == SecretModeKeyring {
+			if err := x.saveKeyringKey(key); err != nil {
+				settings.Mode = SecretModeFile
+			}
+		}
+
+		if settings.Mode == SecretModeFile {
+			if err := x.saveMasterKeyFile(key); err != nil {
+				return "", x.newError(op, masterKeyFileName, err)
+			}
+		}
+
+	default:
+		return "", x.newError(op, secretsFileName, fmt.Errorf("unknown secret mode %q", mode))
+	}
+
+	c, err := newSecretCipher(key)
+	if err != nil {
+		return "", x.newError(op, secretsFileName, err)
+	}
+
+	settings.Check, err = c.encrypt(secretCheckText)
+	if err != nil {
+		return "", x.newError(op, secretsFileName, err)
+	}
+
+	if err := x.saveSecretSettings(settings); err != nil {
+		return "", err
+	}
+
+	x.secrets.mu.Lock()
+	x.secrets.cipher = c
+	x.secrets.mu.Unlock()
+	return settings.Mode, nil
+}
+
+// 解锁加密存储。 [SecretModePassphrase] 使用给定的口令，口令错误时返回 [ErrSecretsLocked] 类别的错误；
+// 其他模式忽略 passphrase 。未启用加密存储时，操作被忽略。
+func (x *ConfigManager) UnlockSecrets(passphrase string) error {
+	const op = "unlock-secrets"
+
+	settings, err := x.loadSecretSettings()
+	if err != nil {
+		return err
+	}
+
+	var key []byte
+	switch settings.Mode {
+	case SecretModeNone:
+		return nil
+
+	case SecretModePassphrase:
+		salt, err := base64.StdEncoding.DecodeString(settings.Salt)
+		if err != nil {
+			return x.newError(op, secretsFileName, wrapCorrupt(err))
+		}
+		key = pbkdf2Sha256([]byte(passphrase), salt, pbkdf2Iterations, 32)
+
+	case SecretModeKeyring:
+		key, err = x.loadKeyringKey()
+		if err != nil {
+			return x.newError(op, secretsFileName, fmt.Errorf("%w: cannot read the master key from the keyring: %v", ErrSecretsLocked, err))
+		}
+
+	case SecretModeFile:
+		key, err = x.loadMasterKeyFile()
+		if err != nil {
+			return x.newError(op, masterKeyFileName, err)
+		}
+
+	default:
+		return x.newError(op, secretsFileName, wrapCorrupt(fmt.Errorf("unknown secret mode %q", settings.Mode)))
+	}
+
+	c, err := newSecretCipher(key)
+	if err != nil {
+		return x.newError(op, secretsFileName, err)
+	}
+
+	if check, err := c.decrypt(settings.Check); err != nil || check != secretCheckText {
+		if settings.Mode == SecretModePassphrase {
+			return x.newError(op, secretsFileName, fmt.Errorf("%w: wrong passphrase", ErrSecretsLocked))
+		}
+		return x.newError(op, secretsFileName, fmt.Errorf("%w: the master key does not match", ErrSecretsLocked))
+	}
+
+	x.secrets.mu.Lock()
+	x.secrets.cipher = c
+	x.secrets.mu.Unlock()
+	return nil
+}
+
+// 设置 clientName 的配置中需要加密保存的字段，通常来自 [SecretMarker] 。
+func (x *ConfigManager) SetSecretKeys(clientName string, keys []string) {
+	x.secrets.mu.Lock()
+	defer x.secrets.mu.Unlock()
+
+	if x.secrets.keys == nil {
+		x.secrets.keys = make(map[string][]string)
+	}
+	x.secrets.keys[clientName] = keys
+}
+
+// 将 clientName 下所有配置中明文的敏感字段原地加密，返回被修改的配置的数量。
+// 需先启用并解锁加密存储。
+func (x *ConfigManager) MigrateSecrets(clientName string) (int, error) {
+	locked, err := x.SecretsLocked()
+	if err != nil {
+		return 0, err
+	}
+
+	mode, _ := x.SecretMode()
+	if mode == SecretModeNone || locked {
+		return 0, x.newError("migrate-secrets", clientName, fmt.Errorf("%w: the secret store must be enabled and unlocked", ErrSecretsLocked))
+	}
+
+	keys, err := x.ListKeys(clientName)
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for _, key := range keys {
+		p, err := x.getKeyFilePath(clientName, key)
+		if err != nil {
+			return count, x.newError("migrate-secrets", clientName+"/"+key, err)
+		}
+
+		content, err := os.ReadFile(p)
+		if err != nil {
+			return count, x.newError("migrate-secrets", clientName+"/"+key, err)
+		}
+
+		// 只处理有明文敏感字段的配置，避免无谓地改写文件。
+		var conf map[string]any
+		if json.Unmarshal(content, &conf) != nil || !x.hasPlainSecrets(clientName, conf) {
+			continue
+		}
+
+		conf, err = x.Load(clientName, key)
+		if err != nil {
+			return count, err
+		}
+
+		if err := x.Save(clientName, key, conf); err != nil {
+			return count, err
+		}
+		count++
+	}
+
+	return count, nil
+}
+
+func (x *ConfigManager) hasPlainSecrets(clientName string, conf map[string]any) bool {
+	x.secrets.mu.RLock()
+	defer x.secrets.mu.RUnlock()
+
+	for _, k := range x.secrets.keys[clientName] {
+		if s, ok := conf[k].(string); ok && s != "" && !strings.HasPrefix(s, encryptedPrefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// 返回 conf 的副本，其中 clientName 的敏感字段被加密。未启用加密存储时返回 conf 本身。
+// 已启用但未解锁时返回 [ErrSecretsLocked] ，以免敏感字段以明文写入。
+func (x *ConfigManager) encryptSecrets(clientName string, conf map[string]any) (map[string]any, error) {
+	x.secrets.mu.RLock()
+	keys := x.secrets.keys[clientName]
+	c := x.secrets.cipher
+	x.secrets.mu.RUnlock()
+
+	if len(keys) == 0 || conf == nil {
+		return conf, nil
+	}
+
+	if c == nil {
+		mode, err := x.SecretMode()
+		if err != nil {
+			return nil, err
+		}
+
+		if mode == SecretModeNone {
+			return conf, nil
+		}
+		return nil, fmt.Errorf("%w: unlock the secret store before saving", ErrSecretsLocked)
+	}
+
+	res := make(map[string]any, len(conf))
+	for k, v := range conf {
+		res[k] = v
+	}
+
+	for _, k := range keys {
+		s, ok := conf[k].(string)
+		if !ok || s == "" || strings.HasPrefix(s, encryptedPrefix) {
+			continue
+		}
+
+		encrypted, err := c.encrypt(s)
+		if err != nil {
+			return nil, err
+		}
+		res[k] = encrypted
+	}
+
+	return res, nil
+}
+
+// 原地解密 conf 中加密的值，不限于当前标记的敏感字段。
+func (x *ConfigManager) decryptSecrets(conf map[string]any) error {
+	x.secrets.mu.RLock()
+	c := x.secrets.cipher
+	x.secrets.mu.RUnlock()
+
+	for k, v := range conf {
+		s, ok := v.(string)
+		if !ok || !strings.HasPrefix(s, encryptedPrefix) {
+			continue
+		}
+
+		if c == nil {
+			return fmt.Errorf("%w: the config contains encrypted values", ErrSecretsLocked)
+		}
+
+		plain, err := c.decrypt(s)
+		if err != nil {
+			return wrapCorrupt(fmt.Errorf("cannot decrypt %s: %v", k, err))
+		}
+		conf[k] = plain
+	}
+
+	return nil
+}
+
+func (x *ConfigManager) loadSecretSettings() (secretSettings, error) {
+	var settings secretSettings
+	p := path.Join(x.rootPath, secretsFileName)
+	content, err := os.ReadFile(p)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return settings, nil
+		}
+		return settings, x.newError("load-secrets", secretsFileName, err)
+	}
+
+	err = json.Unmarshal(content, &settings)
+	if err != nil {
+		return settings, x.newError("load-secrets", secretsFileName, wrapCorrupt(err))
+	}
+	return settings, nil
+}
+
+func (x *ConfigManager) saveSecretSettings(settings secretSettings) error {
+	err := os.MkdirAll(x.rootPath, 0755)
+	if err != nil && !os.IsExist(err) {
+		return x.newError("save-secrets", secretsFileName, err)
+	}
+
+	content, err := json.MarshalIndent(settings, "", "    ")
+	if err != nil {
+		return x.newError("save-secrets", secretsFileName, err)
+	}
+
+	err = os.WriteFile(path.Join(x.rootPath, secretsFileName), content, 0600)
+	if err != nil {
+		return x.newError("save-secrets", secretsFileName, err)
+	}
+	return nil
+}
+
+func (x *ConfigManager) saveMasterKeyFile(key []byte) error {
+	err := os.MkdirAll(x.rootPath, 0755)
+	if err != nil && !os.IsExist(err) {
+		return err
+	}
+	return os.WriteFile(path.Join(x.rootPath, masterKeyFileName), []byte(hex.EncodeToString(key)), 0600)
+}
+
+func (x *ConfigManager) loadMasterKeyFile() ([]byte, error) {
+	content, err := os.ReadFile(path.Join(x.rootPath, masterKeyFileName))
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := hex.DecodeString(strings.TrimSpace(string(content)))
+	if err != nil {
+		return nil, wrapCorrupt(err)
+	}
+	return key, nil
+}
+
+// 密钥环中的条目以根目录的绝对路径区分，不同的配置目录使用不同的主密钥。
+func (x *ConfigManager) keyringAccount() string {
+	abs, err := filepath.Abs(x.rootPath)
+	if err != nil {
+		return x.rootPath
+	}
+	return abs
+}
+
+func (x *ConfigManager) saveKeyringKey(key []byte) error {
+	if systemKeyring == nil {
+		return errKeyringUnavailable
+	}
+	return systemKeyring.Set(x.keyringAccount(), hex.EncodeToString(key))
+}
+
+func (x *ConfigManager) loadKeyringKey() ([]byte, error) {
+	if systemKeyring == nil {
+		return nil, errKeyringUnavailable
+	}
+
+	s, err := systemKeyring.Get(x.keyringAccount())
+	if err != nil {
+		return nil, err
+	}
+	return hex.DecodeString(strings.TrimSpace(s))
+}
+
+// 使用 AES-256-GCM 加解密单个值。
+type secretCipher struct {
+	aead cipher.AEAD
+}
+
+func newSecretCipher(key []byte) (*secretCipher, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &secretCipher{aead}, nil
+}
+
+// 加密后的格式为 encryptedPrefix + base64(nonce + ciphertext) 。
+func (x *secretCipher) encrypt(plain string) (string, error) {
+	nonce := make([]byte, x.aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", err
+	}
+
+	sealed := x.aead.Seal(nonce, nonce, []byte(plain), nil)
+	return encryptedPrefix + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+func (x *secretCipher) decrypt(v string) (string, error) {
+	if !strings.HasPrefix(v, encryptedPrefix) {
+		return "", errors.New("not an encrypted value")
+	}
+
+	sealed, err := base64.StdEncoding.DecodeString(v[len(encryptedPrefix):])
+	if err != nil {
+		return "", err
+	}
+
+	nonceSize := x.aead.NonceSize()
+	if len(sealed) < nonceSize {
+		return "", errors.New("the encrypted value is too short")
+	}
+
+	plain, err := x.aead.Open(nil, sealed[:nonceSize], sealed[nonceSize:], nil)
+	if err != nil {
+		return "", err
+	}
+	return string(plain), nil
+}
+
+// PBKDF2 （ RFC 8018 ），使用 HMAC-SHA256 。
+func pbkdf2Sha256(password, salt []byte, iterations, keyLen int) []byte {
+	prf := hmac.New(sha256.New, password)
+	hashLen := prf.Size()
+	numBlocks := (keyLen + hashLen - 1) / hashLen
+
+	var blockIndex [4]byte
+	dk := make([]byte, 0, numBlocks*hashLen)
+	u := make([]byte, hashLen)
+	for block := 1; block <= numBlocks; block++ {
+		prf.Reset()
+		prf.Write(salt)
+		binary.BigEndian.PutUint32(blockIndex[:], uint32(block))
+		prf.Write(blockIndex[:])
+		dk = prf.Sum(dk)
+
+		t := dk[len(dk)-hashLen:]
+		copy(u, t)
+		for n := 2; n <= iterations; n++ {
+			prf.Reset()
+			prf.Write(u)
+			u = prf.Sum(u[:0])
+			for i := range u {
+				t[i] ^= u[i]
+			}
+		}
+	}
+
+	return dk[:keyLen]
+}
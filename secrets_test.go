@@ -0,0 +1,206 @@
+package client
+
+import (
+	"encoding/hex"
+	"errors"
+	"os"
+	"path"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// 用于测试的密钥环。
+type fakeKeyring map[string]string
+
+func (x fakeKeyring) Set(account, secret string) error {
+	x[account] = secret
+	return nil
+}
+
+func (x fakeKeyring) Get(account string) (string, error) {
+	s, ok := x[account]
+	if !ok {
+		return "", errors.New("not found")
+	}
+	return s, nil
+}
+
+func TestPbkdf2Sha256(t *testing.T) {
+	r := require.New(t)
+
+	// RFC 7914 section 11.
+	r.Equal("55ac046e56e3089fec1691c22544b605", hex.EncodeToString(pbkdf2Sha256([]byte("passwd"), []byte("salt"), 1, 16)))
+	r.Equal("4ddcd8f60b98be21830cee5ef22701f9", hex.EncodeToString(pbkdf2Sha256([]byte("Password"), []byte("NaCl"), 80000, 16)))
+}
+
+func TestSecretCipher(t *testing.T) {
+	r := require.New(t)
+	c, err := newSecretCipher(make([]byte, 32))
+	r.NoError(err)
+
+	v1, err := c.encrypt("secret")
+	r.NoError(err)
+	v2, err := c.encrypt("secret")
+	r.NoError(err)
+	r.True(strings.HasPrefix(v1, encryptedPrefix))
+	r.NotEqual(v1, v2) // Random nonce.
+
+	plain, err := c.decrypt(v1)
+	r.NoError(err)
+	r.Equal("secret", plain)
+
+	_, err = c.decrypt(v1[:len(v1)-4] + "AAAA")
+	r.Error(err)
+}
+
+func TestConfigManager_Secrets(t *testing.T) {
+	readFile := func(clientName, key string) string {
+		content, err := os.ReadFile(path.Join(_CONFIG_PATH, clientName, key+".json"))
+		require.NoError(t, err)
+		return string(content)
+	}
+
+	t.Run("passphrase", func(t *testing.T) {
+		clearAllConfig()
+		r := require.New(t)
+
+		m := NewConfigManager(_CONFIG_PATH)
+		m.SetSecretKeys("C", []string{"Secret"})
+
+		// Saved before the encryption is enabled.
+		r.NoError(m.Save("C", "old", map[string]any{"Secret": "s0", "Other": "o"}))
+		r.Contains(readFile("C", "old"), `"s0"`)
+
+		mode, err := m.EnableSecrets(SecretModePassphrase, "pass")
+		r.NoError(err)
+		r.Equal(SecretModePassphrase, mode)
+
+		_, err = m.EnableSecrets(SecretModeFile, "")
+		r.Error(err)
+
+		r.NoError(m.Save("C", "new", map[string]any{"Secret": "s1", "Other": "o"}))
+		content := readFile("C", "new")
+		r.NotContains(content, `"s1"`)
+		r.Contains(content, `"Other":"o"`)
+		r.Contains(content, encryptedPrefix)
+
+		conf, err := m.Load("C", "new")
+		r.NoError(err)
+		r.Equal(map[string]any{"Secret": "s1", "Other": "o"}, conf)
+
+		// A new instance is locked.
+		m = NewConfigManager(_CONFIG_PATH)
+		m.SetSecretKeys("C", []string{"Secret"})
+		locked, err := m.SecretsLocked()
+		r.NoError(err)
+		r.True(locked)
+
+		_, err = m.Load("C", "new")
+		r.ErrorIs(err, ErrSecretsLocked)
+
+		err = m.Save("C", "new", map[string]any{"Secret": "s1"})
+		r.ErrorIs(err, ErrSecretsLocked)
+
+		// Plaintext configs can still be read.
+		conf, err = m.Load("C", "old")
+		r.NoError(err)
+		r.Equal("s0", conf["Secret"])
+
+		err = m.UnlockSecrets("wrong")
+		r.ErrorIs(err, ErrSecretsLocked)
+		r.EqualError(err, "unlock-secrets secrets.json: secrets locked: wrong passphrase")
+
+		r.NoError(m.UnlockSecrets("pass"))
+		conf, err = m.Load("C", "new")
+		r.NoError(err)
+		r.Equal("s1", conf["Secret"])
+
+		// Migrate in place.
+		n, err := m.MigrateSecrets("C")
+		r.NoError(err)
+		r.Equal(1, n)
+		r.NotContains(readFile("C", "old"), `"s0"`)
+
+		conf, err = m.Load("C", "old")
+		r.NoError(err)
+		r.Equal(map[string]any{"Secret": "s0", "Other": "o"}, conf)
+
+		n, err = m.MigrateSecrets("C")
+		r.NoError(err)
+		r.Equal(0, n)
+	})
+
+	t.Run("keyring", func(t *testing.T) {
+		clearAllConfig()
+		r := require.New(t)
+
+		old := systemKeyring
+		defer func() { systemKeyring = old }()
+		kr := fakeKeyring{}
+		systemKeyring = kr
+
+		m := NewConfigManager(_CONFIG_PATH)
+		mode, err := m.EnableSecrets(SecretModeKeyring, "")
+		r.NoError(err)
+		r.Equal(SecretModeKeyring, mode)
+		r.Len(kr, 1)
+
+		_, err = os.Stat(path.Join(_CONFIG_PATH, masterKeyFileName))
+		r.True(os.IsNotExist(err))
+
+		m = NewConfigManager(_CONFIG_PATH)
+		r.NoError(m.UnlockSecrets(""))
+
+		// The key is lost.
+		for k := range kr {
+			delete(kr, k)
+		}
+		m = NewConfigManager(_CONFIG_PATH)
+		r.ErrorIs(m.UnlockSecrets(""), ErrSecretsLocked)
+	})
+
+	t.Run("file-fallback", func(t *testing.T) {
+		clearAllConfig()
+		r := require.New(t)
+
+		old := systemKeyring
+		defer func() { systemKeyring = old }()
+		systemKeyring = nil
+
+		m := NewConfigManager(_CONFIG_PATH)
+		mode, err := m.EnableSecrets(SecretModeKeyring, "")
+		r.NoError(err)
+		r.Equal(SecretModeFile, mode)
+
+		info, err := os.Stat(path.Join(_CONFIG_PATH, masterKeyFileName))
+		r.NoError(err)
+		r.Equal(os.FileMode(0600), info.Mode().Perm())
+
+		m = NewConfigManager(_CONFIG_PATH)
+		r.NoError(m.UnlockSecrets(""))
+	})
+
+	t.Run("history", func(t *testing.T) {
+		clearAllConfig()
+		r := require.New(t)
+
+		m := NewConfigManager(_CONFIG_PATH)
+		m.SetSecretKeys("C", []string{"Secret"})
+		_, err := m.EnableSecrets(SecretModePassphrase, "pass")
+		r.NoError(err)
+
+		// 历史记录中的敏感字段不被保存。
+		r.NoError(m.AppendHistory("C", HistoryEntry{URL: "u", Config: map[string]any{"Secret": "secret1"}}))
+		content, err := os.ReadFile(path.Join(_CONFIG_PATH, "C", historyFileName))
+		r.NoError(err)
+		r.NotContains(string(content), "secret1")
+
+		entries, err := m.LoadHistory("C")
+		r.NoError(err)
+		r.Equal(RedactedText, entries[0].Config["Secret"])
+	})
+
+	clearAllConfig()
+}
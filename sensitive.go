@@ -0,0 +1,119 @@
+package client
+
+import (
+	"sort"
+	"strings"
+
+	"fyne.io/fyne/v2/data/binding"
+	"fyne.io/fyne/v2/widget"
+)
+
+// 可选接口。 [Client] 实现此接口以声明其配置中的敏感字段，如密钥。敏感字段：
+//   - 在界面上应使用 [NewSensitiveEntry] 编辑，默认不显示明文。
+//   - 启用加密存储时被加密保存，见 [ConfigManager.EnableSecrets] 。
+//   - 在请求历史中被替换为 [RedactedText] ，导出的代码片段中默认也被替换。
+type SecretMarker interface {
+	// 返回敏感字段，即 [Client.GetConfig] 返回的 map 中的 key 。仅字符串类型的值会被处理。
+	SecretKeys() []string
+}
+
+// 替换敏感值所用的文本。
+const RedactedText = "******"
+
+// 在文本中替换敏感值时，短于此长度的值被忽略，以免把常见的短字符串也替换掉。
+const minRedactLength = 4
+
+// 创建编辑敏感字段的输入框：默认以密码的形式展示，可通过右侧的按钮切换是否显示明文。
+func NewSensitiveEntry(data binding.String) *widget.Entry {
+	entry := widget.NewPasswordEntry()
+	entry.Bind(data)
+	return entry
+}
+
+// 返回 config 中 keys 对应的非空字符串值。 vars 不为空时，一并返回替换变量后的值，
+// 因为实际发出的请求中使用的是替换后的值。
+func SensitiveValues(config map[string]any, keys []string, vars map[string]string) []string {
+	var res []string
+	for _, k := range keys {
+		s, ok := config[k].(string)
+		if !ok || s == "" || s == RedactedText {
+			continue
+		}
+
+		res = append(res, s)
+		if expanded := ExpandVariables(s, vars); expanded != s {
+			res = append(res, expanded)
+		}
+	}
+	return res
+}
+
+// 将 s 中出现的敏感值替换为 [RedactedText] 。
+func Redact(s string, values []string) string {
+	if len(values) == 0 {
+		return s
+	}
+
+	// 先替换长的值，避免一个值是另一个值的一部分时替换不完整。
+	sorted := append([]string(nil), values...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return len(sorted[i]) > len(sorted[j])
+	})
+
+	for _, v := range sorted {
+		if len(v) >= minRedactLength {
+			s = strings.ReplaceAll(s, v, RedactedText)
+		}
+	}
+	return s
+}
+
+// 返回 config 的副本，其中 keys 对应的非空字符串值被替换为 [RedactedText] 。
+func RedactConfig(config map[string]any, keys []string) map[string]any {
+	if config == nil || len(keys) == 0 {
+		return config
+	}
+
+	res := make(map[string]any, len(config))
+	for k, v := range config {
+		res[k] = v
+	}
+
+	for _, k := range keys {
+		if s, ok := res[k].(string); ok && s != "" {
+			res[k] = RedactedText
+		}
+	}
+	return res
+}
+
+// 返回 config 的副本，其中被 [RedactConfig] 替换掉的敏感字段，用 from 中对应的值恢复。
+// 用于载入已脱敏的历史记录： from 通常是界面上当前的配置。
+func RestoreRedacted(config, from map[string]any, keys []string) map[string]any {
+	if config == nil || len(keys) == 0 {
+		return config
+	}
+
+	res := make(map[string]any, len(config))
+	for k, v := range config {
+		res[k] = v
+	}
+
+	for _, k := range keys {
+		if res[k] == RedactedText {
+			res[k] = from[k]
+		}
+	}
+	return res
+}
+
+// 对历史记录脱敏：配置中的敏感字段被替换，文本中出现的敏感值也被替换。
+func redactHistoryEntry(entry HistoryEntry, keys []string) HistoryEntry {
+	values := SensitiveValues(entry.Config, keys, nil)
+	entry.URL = Redact(entry.URL, values)
+	entry.Param = Redact(entry.Param, values)
+	entry.Response = Redact(entry.Response, values)
+	entry.Error = Redact(entry.Error, values)
+	entry.Config = RedactConfig(entry.Config, keys)
+	return entry
+}
@@ -0,0 +1,77 @@
+package client
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSensitiveValues(t *testing.T) {
+	r := require.New(t)
+	config := map[string]any{
+		"Key":    "k",
+		"Secret": "s-{{env}}",
+		"Empty":  "",
+		"Num":    1,
+	}
+	keys := []string{"Secret", "Empty", "Num", "Missing"}
+
+	r.Equal([]string{"s-{{env}}"}, SensitiveValues(config, keys, nil))
+	r.Equal([]string{"s-{{env}}", "s-dev"}, SensitiveValues(config, keys, map[string]string{"env": "dev"}))
+	r.Empty(SensitiveValues(map[string]any{"Secret": RedactedText}, keys, nil))
+}
+
+func TestRedact(t *testing.T) {
+	r := require.New(t)
+	r.Equal("a", Redact("a", nil))
+	r.Equal("x ****** y ******", Redact("x secret y secret", []string{"secret"}))
+
+	// 短的值不被替换。
+	r.Equal("abc", Redact("abc", []string{"abc"}))
+
+	// 长的值先被替换。
+	r.Equal("1 ******, 2 ******", Redact("1 secret-long, 2 secret", []string{"secret", "secret-long"}))
+}
+
+func TestRedactConfig(t *testing.T) {
+	r := require.New(t)
+	config := map[string]any{"Key": "k", "Secret": "s", "Empty": ""}
+
+	redacted := RedactConfig(config, []string{"Secret", "Empty"})
+	r.Equal(map[string]any{"Key": "k", "Secret": RedactedText, "Empty": ""}, redacted)
+	r.Equal("s", config["Secret"], "the source should not be modified")
+
+	restored := RestoreRedacted(redacted, map[string]any{"Key": "k2", "Secret": "s2"}, []string{"Secret", "Empty"})
+	r.Equal(map[string]any{"Key": "k", "Secret": "s2", "Empty": ""}, restored)
+	r.Equal(RedactedText, redacted["Secret"], "the source should not be modified")
+
+	r.Nil(RedactConfig(nil, []string{"Secret"}))
+	r.Equal(config, RedactConfig(config, nil))
+}
+
+func TestConfigManager_AppendHistory_redact(t *testing.T) {
+	clearAllConfig()
+	r := require.New(t)
+
+	m := NewConfigManager(_CONFIG_PATH)
+	m.SetSecretKeys("C", []string{"Secret"})
+	r.NoError(m.AppendHistory("C", HistoryEntry{
+		URL:      "http://temp.org/?token=my-secret",
+		Param:    `{"a":"my-secret"}`,
+		Response: "echo my-secret",
+		Error:    "bad my-secret",
+		Config:   map[string]any{"Key": "k", "Secret": "my-secret"},
+	}))
+
+	entries, err := m.LoadHistory("C")
+	r.NoError(err)
+	r.Equal(HistoryEntry{
+		URL:      "http://temp.org/?token=******",
+		Param:    `{"a":"******"}`,
+		Response: "echo ******",
+		Error:    "bad ******",
+		Config:   map[string]any{"Key": "k", "Secret": RedactedText},
+	}, entries[0])
+
+	clearAllConfig()
+}
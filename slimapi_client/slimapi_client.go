@@ -0,0 +1,373 @@
+package slimapi_client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/data/binding"
+	"fyne.io/fyne/v2/widget"
+	"github.com/cmstar/go-errx"
+	client "github.com/cmstar/go-webapi-client"
+)
+
+const (
+	_URI             = "Uri"
+	_METHOD          = "Method"
+	_HTTP_METHOD     = "HttpMethod"
+	_ENCODING        = "Encoding"
+	_RESPONSE_FORMAT = "ResponseFormat"
+	_CALLBACK        = "Callback"
+	_PARAM           = "Param"
+	_TRANSPORT       = client.TransportConfigKey
+)
+
+type SlimApiClient struct {
+	uri            binding.String
+	method         binding.String
+	httpMethod     binding.String
+	encoding       binding.String
+	responseFormat binding.String
+	callback       binding.String
+	param          binding.String
+	result         *client.ResponseView
+	transport      *client.TransportSettings
+	runner         *client.RequestRunner
+	box            fyne.CanvasObject // 见 Box 。
+
+	mu             sync.Mutex                // 保护 vars 和 historyHandler 。
+	vars           map[string]string         // 当前环境的变量，见 [client.VariableSetter] 。
+	historyHandler func(client.HistoryEntry) // 见 [client.HistoryReporter] 。
+}
+
+var (
+	_ client.Client          = (*SlimApiClient)(nil)
+	_ client.Executor        = (*SlimApiClient)(nil)
+	_ client.VariableSetter  = (*SlimApiClient)(nil)
+	_ client.HistoryReporter = (*SlimApiClient)(nil)
+	_ client.Submitter       = (*SlimApiClient)(nil)
+	_ client.Exporter        = (*SlimApiClient)(nil)
+	_ client.Importer        = (*SlimApiClient)(nil)
+	_ client.ChangeNotifier  = (*SlimApiClient)(nil)
+)
+
+// 创建一个 [*SlimApiClient] 。
+func NewClient() *SlimApiClient {
+	x := &SlimApiClient{
+		uri:            binding.NewString(),
+		method:         binding.NewString(),
+		httpMethod:     binding.NewString(),
+		encoding:       binding.NewString(),
+		responseFormat: binding.NewString(),
+		callback:       binding.NewString(),
+		param:          binding.NewString(),
+		result:         client.NewResponseView(),
+		transport:      client.NewTransportSettings(),
+		runner:         client.NewRequestRunner(),
+	}
+	x.httpMethod.Set(http.MethodPost)
+	x.encoding.Set(EncodingJson)
+	x.responseFormat.Set(ResponseFormatJson)
+	return x
+}
+
+func (x *SlimApiClient) Name() string {
+	return "SlimApi"
+}
+
+func (x *SlimApiClient) Title() string {
+	return "SlimAPI"
+}
+
+func (x *SlimApiClient) GetConfig() map[string]any {
+	uri, _ := x.uri.Get()
+	method, _ := x.method.Get()
+	httpMethod, _ := x.httpMethod.Get()
+	encoding, _ := x.encoding.Get()
+	responseFormat, _ := x.responseFormat.Get()
+	callback, _ := x.callback.Get()
+	param, _ := x.param.Get()
+
+	return map[string]any{
+		_URI:             uri,
+		_METHOD:          method,
+		_HTTP_METHOD:     httpMethod,
+		_ENCODING:        encoding,
+		_RESPONSE_FORMAT: responseFormat,
+		_CALLBACK:        callback,
+		_PARAM:           param,
+		_TRANSPORT:       x.transport.GetConfig(),
+	}
+}
+
+func (x *SlimApiClient) SetConfig(config map[string]any) {
+	read := func(name string) string {
+		v, ok := config[name]
+		if !ok {
+			x.result.SetText("missing config key: " + name)
+			return ""
+		}
+
+		s, ok := v.(string)
+		if !ok {
+			x.result.SetText("config value error, key: " + name)
+			return ""
+		}
+
+		return s
+	}
+
+	x.uri.Set(read(_URI))
+	x.method.Set(read(_METHOD))
+	x.httpMethod.Set(read(_HTTP_METHOD))
+	x.encoding.Set(read(_ENCODING))
+	x.responseFormat.Set(read(_RESPONSE_FORMAT))
+	x.callback.Set(read(_CALLBACK))
+	x.param.Set(read(_PARAM))
+
+	// 早期的配置没有此项，缺失时使用默认值，不视为错误。
+	x.transport.SetConfig(config[_TRANSPORT])
+}
+
+// 返回界面。界面只创建一次，其中的绑定和监听器也只注册一次，每次切换到此 Client 时不会累积。
+func (x *SlimApiClient) Box() fyne.CanvasObject {
+	if x.box == nil {
+		x.box = x.makeBox()
+	}
+	return x.box
+}
+
+func (x *SlimApiClient) makeBox() fyne.CanvasObject {
+	paramInput := widget.NewMultiLineEntry()
+	paramInput.Bind(x.param)
+
+	requestForm := &widget.Form{
+		Items: []*widget.FormItem{
+			{Text: "URL", Widget: widget.NewEntryWithData(x.uri)},
+			{Text: "Method", Widget: widget.NewEntryWithData(x.method)},
+			{Text: "HTTP", Widget: client.NewBoundSelect([]string{http.MethodGet, http.MethodPost}, x.httpMethod)},
+			{Text: "Encoding", Widget: client.NewBoundSelect([]string{EncodingGet, EncodingForm, EncodingJson}, x.encoding)},
+			{Text: "Response", Widget: client.NewBoundSelect([]string{ResponseFormatJson, ResponseFormatPlain, ResponseFormatJsonp}, x.responseFormat)},
+			{Text: "Callback", Widget: widget.NewEntryWithData(x.callback)},
+			{Text: "Param", Widget: paramInput},
+		},
+	}
+
+	container := container.NewHSplit(
+		container.NewVScroll(container.NewVBox(requestForm, x.runner.Buttons(x.onSubmit), x.transport.Box())),
+		x.result.Box(),
+	)
+
+	return container
+}
+
+func (x *SlimApiClient) onSubmit() {
+	// 采用异步请求，同一时间只执行一个请求，正在执行时提交操作被忽略。
+	x.runner.Run(func(ctx context.Context) {
+		x.result.SetText("requesting ...")
+
+		// 发现更新 binding.String 速度太快会来不及反馈到界面上。等一下下。
+		<-time.After(200 * time.Millisecond)
+
+		config := x.GetConfig()
+		request, err := x.Request()
+		if err != nil {
+			x.result.SetText(err.Error())
+			return
+		}
+
+		start := time.Now()
+		response, err := x.performRequest(ctx, request)
+
+		switch {
+		case err != nil && ctx.Err() != nil:
+			x.result.SetText(client.CancelledText)
+		case err != nil:
+			x.result.SetText(err.Error())
+		default:
+			x.result.SetResponse(response)
+		}
+
+		x.mu.Lock()
+		handler := x.historyHandler
+		x.mu.Unlock()
+
+		if handler != nil {
+			handler(client.NewHistoryEntry(config, request.URL, request.Param, start, response, err))
+		}
+	})
+}
+
+// 实现 [client.Submitter] 。
+func (x *SlimApiClient) Submit() {
+	x.onSubmit()
+}
+
+// 实现 [client.ChangeNotifier] 。
+func (x *SlimApiClient) AddChangeListener(fn func()) {
+	client.AddBindingListener(fn, x.uri, x.method, x.httpMethod, x.encoding, x.responseFormat, x.callback, x.param)
+	x.transport.AddChangeListener(fn)
+}
+
+// 实现 [client.HistoryReporter] 。
+func (x *SlimApiClient) SetHistoryHandler(handler func(entry client.HistoryEntry)) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	x.historyHandler = handler
+}
+
+// 使用当前的配置执行请求，用于命令行模式。实现 [client.Executor] 。
+// 响应的状态码不是 2xx 时返回错误。
+func (x *SlimApiClient) Execute(ctx context.Context) (string, error) {
+	request, err := x.Request()
+	if err != nil {
+		return "", err
+	}
+
+	response, err := request.Execute(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	responseText := response.FormatBody()
+	if !response.IsSuccess() {
+		return "", fmt.Errorf("%s\n%s", response.Status, responseText)
+	}
+	return responseText, nil
+}
+
+// 实现 [client.Exporter] 。 SlimAPI 不需要签名， signed 被忽略。
+func (x *SlimApiClient) ExportRequest(signed bool) (*client.ExportedRequest, error) {
+	request, err := x.Request()
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := request.Build(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	return client.NewExportedRequest(req)
+}
+
+// 实现 [client.Importer] 。 URL 上带有 SlimAPI 的元参数（ ~method 、 ~format 、 ~callback ）时匹配。
+func (x *SlimApiClient) MatchRequest(req *client.CurlRequest) bool {
+	u, err := url.Parse(req.URL)
+	if err != nil {
+		return false
+	}
+
+	query := u.Query()
+	return query.Has("~method") || query.Has("~format") || query.Has("~callback")
+}
+
+// 实现 [client.Importer] 。 URL 上的元参数被转换为对应的字段，其余部分保持不变。
+func (x *SlimApiClient) ImportRequest(req *client.CurlRequest) (map[string]any, error) {
+	if req.Method != http.MethodGet && req.Method != http.MethodPost {
+		return nil, fmt.Errorf("SlimAPI only supports GET and POST requests, got %s", req.Method)
+	}
+
+	u, err := url.Parse(req.URL)
+	if err != nil {
+		return nil, err
+	}
+
+	// 其余参数保持原样，如 ?METHOD 形式的方法名称，见 [client.RemoveQueryParams] 。
+	query := u.Query()
+	method := query.Get("~method")
+	callback := query.Get("~callback")
+	format := strings.Split(query.Get("~format"), ",")
+	uri := client.RemoveQueryParams(req.URL, "~method", "~callback", "~format")
+
+	responseFormat := ResponseFormatJson
+	switch {
+	case callback != "":
+		responseFormat = ResponseFormatJsonp
+	case len(format) > 1 && format[1] == ResponseFormatPlain:
+		responseFormat = ResponseFormatPlain
+	}
+
+	// ~format 中的请求格式优先，其次是 Content-Type 。
+	var encoding string
+	contentType := req.Header.Get("Content-Type")
+	switch {
+	case format[0] != "":
+		encoding = format[0]
+	case strings.Contains(contentType, "json"):
+		encoding = EncodingJson
+	case strings.Contains(contentType, "x-www-form-urlencoded"):
+		encoding = EncodingForm
+	case req.Body == "":
+		encoding = EncodingGet
+	default:
+		encoding = EncodingJson
+	}
+
+	return map[string]any{
+		_URI:             uri,
+		_METHOD:          method,
+		_HTTP_METHOD:     req.Method,
+		_ENCODING:        encoding,
+		_RESPONSE_FORMAT: responseFormat,
+		_CALLBACK:        callback,
+		_PARAM:           req.Body,
+	}, nil
+}
+
+// 实现 [client.VariableSetter] 。
+func (x *SlimApiClient) SetVariables(vars map[string]string) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	x.vars = vars
+}
+
+// 根据当前界面上的值创建 [SlimApiRequest] ，各字段中的变量已被替换。
+// 连接参数有误时返回 error 。
+func (x *SlimApiClient) Request() (*SlimApiRequest, error) {
+	x.mu.Lock()
+	vars := x.vars
+	x.mu.Unlock()
+
+	get := func(v binding.String) string {
+		s, _ := v.Get()
+		return client.ExpandVariables(s, vars)
+	}
+
+	// 以下几项来自下拉框，不需要替换变量。
+	httpMethod, _ := x.httpMethod.Get()
+	encoding, _ := x.encoding.Get()
+	responseFormat, _ := x.responseFormat.Get()
+
+	httpClient, err := x.transport.NewHttpClient()
+	if err != nil {
+		return nil, err
+	}
+
+	return &SlimApiRequest{
+		URL:            get(x.uri),
+		Method:         get(x.method),
+		HttpMethod:     httpMethod,
+		Encoding:       encoding,
+		ResponseFormat: responseFormat,
+		Callback:       get(x.callback),
+		Param:          get(x.param),
+
+		HttpClient: httpClient,
+	}, nil
+}
+
+func (x *SlimApiClient) performRequest(ctx context.Context, request *SlimApiRequest) (response *client.Response, err error) {
+	defer func() {
+		if err == nil {
+			err = errx.PreserveRecover("", recover())
+		}
+	}()
+
+	return request.Execute(ctx)
+}
@@ -0,0 +1,158 @@
+package slimapi_client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/cmstar/go-httplib/headers"
+	client "github.com/cmstar/go-webapi-client"
+)
+
+// 请求参数的编码方式，对应 SlimAPI 的 ~format 元参数中请求格式的部分。
+const (
+	EncodingGet  = "get"  // 参数以 query string 的形式放在 URL 上。
+	EncodingForm = "post" // 参数以 application/x-www-form-urlencoded 的形式放在 body 中。
+	EncodingJson = "json" // 参数以 JSON 的形式放在 body 中。
+)
+
+// 回执格式。
+const (
+	ResponseFormatJson  = "json"  // 默认格式， Content-Type: application/json 。
+	ResponseFormatPlain = "plain" // 内容同 JSON ，但 Content-Type: text/plain 。对应 ~format 中的 plain 。
+	ResponseFormatJsonp = "jsonp" // JSONP 格式，需给定 Callback 。对应 ~callback 元参数。
+)
+
+// 描述一个 SlimAPI 协议的请求，不依赖界面，可直接在代码中使用。
+type SlimApiRequest struct {
+	URL    string // API 的入口地址。
+	Method string // API 方法的名称，对应 ~method 元参数。为空时，认为 URL 中已经包含方法名称。
+
+	// HTTP 请求的 METHOD ， GET 或 POST 。为空时， [EncodingGet] 使用 GET ，其余使用 POST 。
+	HttpMethod string
+
+	// 参数的编码方式，为 EncodingXxx 之一。为空时，默认为 [EncodingGet] 。
+	Encoding string
+
+	// 回执格式，为 ResponseFormatXxx 之一。为空时，默认为 [ResponseFormatJson] 。
+	ResponseFormat string
+
+	// JSONP 回调函数的名称，仅在 [ResponseFormatJsonp] 时有效。
+	Callback string
+
+	// 请求的参数。 [EncodingJson] 时为 JSON ，其余为形如 a=1&b=2 的格式。
+	Param string
+
+	// 执行请求所用的 [http.Client] 。若为 nil ，使用 [http.DefaultClient] 。
+	HttpClient *http.Client
+}
+
+// 执行请求。
+// 仅当请求无法发出或响应无法读取时返回 error ，非 2xx 的响应不被视为错误。
+func (x *SlimApiRequest) Execute(ctx context.Context) (*client.Response, error) {
+	request, err := x.Build(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return client.SendRequest(x.HttpClient, request)
+}
+
+// 根据给定的参数构建 [http.Request] 。
+func (x *SlimApiRequest) Build(ctx context.Context) (*http.Request, error) {
+	encoding := x.Encoding
+	if encoding == "" {
+		encoding = EncodingGet
+	}
+
+	httpMethod := x.HttpMethod
+	if httpMethod == "" {
+		if encoding == EncodingGet {
+			httpMethod = http.MethodGet
+		} else {
+			httpMethod = http.MethodPost
+		}
+	}
+
+	// URL 上已有的 query string 保持原样，如 ?METHOD 形式的方法名称，见 [client.AppendQuery] ；
+	// 给定的元参数替换 URL 上的同名参数。
+	var meta, query []client.KeyValue
+	setMeta := func(name, value string) {
+		meta = append(meta, client.KeyValue{Name: name, Value: value})
+	}
+
+	if x.Method != "" {
+		setMeta("~method", x.Method)
+	}
+
+	// ~format 的优先级高于 Content-Type ，若要指定 plain ，需将请求格式一并写上。
+	switch x.ResponseFormat {
+	case "", ResponseFormatJson:
+	case ResponseFormatPlain:
+		setMeta("~format", encoding+","+ResponseFormatPlain)
+	case ResponseFormatJsonp:
+		if x.Callback == "" {
+			return nil, fmt.Errorf("the callback is required for JSONP")
+		}
+		setMeta("~callback", x.Callback)
+	default:
+		return nil, fmt.Errorf("unsupported response format %q", x.ResponseFormat)
+	}
+
+	var body io.Reader
+	var contentType string
+	switch encoding {
+	case EncodingGet:
+		params, err := client.ParseFormFields(x.Param)
+		if err != nil {
+			return nil, fmt.Errorf("the request params are not valid: %w", err)
+		}
+		query = params
+
+	case EncodingForm:
+		if _, err := url.ParseQuery(x.Param); err != nil {
+			return nil, fmt.Errorf("the request params are not valid: %w", err)
+		}
+		body = strings.NewReader(x.Param)
+		contentType = "application/x-www-form-urlencoded"
+
+	case EncodingJson:
+		if !json.Valid([]byte(x.Param)) {
+			return nil, fmt.Errorf("the request message is not a valid JSON")
+		}
+		body = strings.NewReader(x.Param)
+		contentType = "application/json"
+
+	default:
+		return nil, fmt.Errorf("unsupported encoding %q", x.Encoding)
+	}
+
+	if body != nil && httpMethod == http.MethodGet {
+		return nil, fmt.Errorf("the encoding %q requires a POST request", encoding)
+	}
+
+	metaNames := make([]string, 0, len(meta))
+	for _, kv := range meta {
+		metaNames = append(metaNames, kv.Name)
+	}
+
+	uri, err := client.AppendQuery(client.RemoveQueryParams(x.URL, metaNames...), append(meta, query...))
+	if err != nil {
+		return nil, err
+	}
+
+	request, err := http.NewRequestWithContext(ctx, httpMethod, uri, body)
+	if err != nil {
+		return nil, err
+	}
+
+	if contentType != "" {
+		request.Header.Set(headers.ContentType, contentType)
+	}
+
+	return request, nil
+}
@@ -0,0 +1,182 @@
+package slimapi_client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/cmstar/go-logx"
+	"github.com/cmstar/go-webapi"
+	client "github.com/cmstar/go-webapi-client"
+	"github.com/cmstar/go-webapi/slimapi"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestServer() *httptest.Server {
+	handler := slimapi.NewSlimApiHandler("test")
+	handler.RegisterMethod(webapi.ApiMethod{
+		Name: "Test",
+		Value: reflect.ValueOf(func(req struct{ S1, S2 string }) string {
+			return req.S1 + "," + req.S2
+		}),
+	})
+
+	logger := logx.NopLogger
+	handlerFunc := webapi.CreateHandlerFunc(handler, logx.NewSingleLoggerLogFinder(logger))
+	return httptest.NewServer(http.HandlerFunc(handlerFunc))
+}
+
+func TestSlimApiRequest_Execute(t *testing.T) {
+	ts := newTestServer()
+	defer ts.Close()
+
+	cases := []struct {
+		name        string
+		req         SlimApiRequest
+		contentType string
+		body        string
+	}{
+		{
+			name:        "get",
+			req:         SlimApiRequest{Method: "Test", Encoding: EncodingGet, Param: "S1=a&S2=b"},
+			contentType: "application/json",
+			body:        `{"Code":0,"Message":"","Data":"a,b"}`,
+		},
+		{
+			name:        "nameless-method",
+			req:         SlimApiRequest{URL: "?Test", Encoding: EncodingGet, Param: "S2=b&S1=a"},
+			contentType: "application/json",
+			body:        `{"Code":0,"Message":"","Data":"a,b"}`,
+		},
+		{
+			name:        "nameless-method-plain",
+			req:         SlimApiRequest{URL: "?Test", Encoding: EncodingJson, ResponseFormat: ResponseFormatPlain, Param: `{"S1":"a","S2":"b"}`},
+			contentType: "text/plain",
+			body:        `{"Code":0,"Message":"","Data":"a,b"}`,
+		},
+		{
+			name:        "form",
+			req:         SlimApiRequest{Method: "Test", Encoding: EncodingForm, Param: "S1=a&S2=b"},
+			contentType: "application/json",
+			body:        `{"Code":0,"Message":"","Data":"a,b"}`,
+		},
+		{
+			name:        "json",
+			req:         SlimApiRequest{Method: "Test", Encoding: EncodingJson, Param: `{"S1":"a","S2":"b"}`},
+			contentType: "application/json",
+			body:        `{"Code":0,"Message":"","Data":"a,b"}`,
+		},
+		{
+			name:        "json-plain",
+			req:         SlimApiRequest{Method: "Test", Encoding: EncodingJson, ResponseFormat: ResponseFormatPlain, Param: `{"S1":"a","S2":"b"}`},
+			contentType: "text/plain",
+			body:        `{"Code":0,"Message":"","Data":"a,b"}`,
+		},
+		{
+			name:        "jsonp",
+			req:         SlimApiRequest{Method: "Test", ResponseFormat: ResponseFormatJsonp, Callback: "cb", Param: "S1=a"},
+			contentType: "text/javascript",
+			body:        `cb({"Code":0,"Message":"","Data":"a,"})`,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			r := require.New(t)
+			c.req.URL = ts.URL + c.req.URL
+			res, err := c.req.Execute(context.Background())
+			r.NoError(err)
+			r.Equal(200, res.StatusCode)
+			r.Contains(res.Header.Get("Content-Type"), c.contentType)
+			r.Equal(c.body, string(res.Body))
+		})
+	}
+}
+
+func TestSlimApiRequest_Build(t *testing.T) {
+	r := require.New(t)
+
+	req := &SlimApiRequest{URL: "http://temp.org/api?x=1", Method: "M", Param: "a=1&b=2"}
+	httpReq, err := req.Build(context.Background())
+	r.NoError(err)
+	r.Equal(http.MethodGet, httpReq.Method)
+	r.Equal("http://temp.org/api?x=1&~method=M&a=1&b=2", httpReq.URL.String())
+
+	// URL 上已有的参数保持原样，同名的元参数被替换。
+	req = &SlimApiRequest{URL: "http://temp.org/api?Old&~method=Old&b=2", Method: "M", Param: "a=1"}
+	httpReq, err = req.Build(context.Background())
+	r.NoError(err)
+	r.Equal("http://temp.org/api?Old&b=2&~method=M&a=1", httpReq.URL.String())
+
+	_, err = (&SlimApiRequest{URL: "http://temp.org/", Encoding: EncodingJson, Param: "{"}).Build(context.Background())
+	r.EqualError(err, "the request message is not a valid JSON")
+
+	_, err = (&SlimApiRequest{URL: "http://temp.org/", Encoding: EncodingJson, HttpMethod: http.MethodGet, Param: "{}"}).Build(context.Background())
+	r.EqualError(err, `the encoding "json" requires a POST request`)
+
+	_, err = (&SlimApiRequest{URL: "http://temp.org/", ResponseFormat: ResponseFormatJsonp}).Build(context.Background())
+	r.EqualError(err, "the callback is required for JSONP")
+}
+
+func TestSlimApiClient_ImportRequest(t *testing.T) {
+	c := NewClient()
+
+	t.Run("json", func(t *testing.T) {
+		r := require.New(t)
+		req, err := client.ParseCurl(`curl 'http://localhost/api?~method=Test&~format=json,plain' --json '{"S1":"a"}'`)
+		r.NoError(err)
+		r.True(c.MatchRequest(req))
+
+		conf, err := c.ImportRequest(req)
+		r.NoError(err)
+		r.Equal(map[string]any{
+			_URI:             "http://localhost/api",
+			_METHOD:          "Test",
+			_HTTP_METHOD:     http.MethodPost,
+			_ENCODING:        EncodingJson,
+			_RESPONSE_FORMAT: ResponseFormatPlain,
+			_CALLBACK:        "",
+			_PARAM:           `{"S1":"a"}`,
+		}, conf)
+	})
+
+	t.Run("get", func(t *testing.T) {
+		r := require.New(t)
+		req, err := client.ParseCurl(`curl 'http://localhost/api/Test?S1=a&~callback=cb'`)
+		r.NoError(err)
+		r.True(c.MatchRequest(req))
+
+		conf, err := c.ImportRequest(req)
+		r.NoError(err)
+		r.Equal("http://localhost/api/Test?S1=a", conf[_URI])
+		r.Equal(http.MethodGet, conf[_HTTP_METHOD])
+		r.Equal(EncodingGet, conf[_ENCODING])
+		r.Equal(ResponseFormatJsonp, conf[_RESPONSE_FORMAT])
+		r.Equal("cb", conf[_CALLBACK])
+	})
+
+	// 其余参数保持原样，包括 ?METHOD 形式的方法名称。
+	t.Run("nameless-method", func(t *testing.T) {
+		r := require.New(t)
+		req, err := client.ParseCurl(`curl 'http://localhost/api?Test&S2=b&~callback=cb&S1=a'`)
+		r.NoError(err)
+
+		conf, err := c.ImportRequest(req)
+		r.NoError(err)
+		r.Equal("http://localhost/api?Test&S2=b&S1=a", conf[_URI])
+		r.Equal("", conf[_METHOD])
+		r.Equal("cb", conf[_CALLBACK])
+	})
+
+	t.Run("no-match", func(t *testing.T) {
+		r := require.New(t)
+		req, err := client.ParseCurl(`curl -X DELETE http://localhost/api`)
+		r.NoError(err)
+		r.False(c.MatchRequest(req))
+
+		_, err = c.ImportRequest(req)
+		r.EqualError(err, "SlimAPI only supports GET and POST requests, got DELETE")
+	})
+}
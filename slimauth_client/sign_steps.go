@@ -0,0 +1,162 @@
+package slimauth_client
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cmstar/go-webapi"
+	"github.com/cmstar/go-webapi/slimauth"
+)
+
+// 签名的计算过程，用于排查签名被拒绝的原因（如时钟偏差），或核对其他语言的 SlimAuth 实现。
+type SignSteps struct {
+	Timestamp     int64  // 签名使用的时间戳（ Unix 秒）。
+	Version       int    // 签名算法的版本。
+	DataToSign    string // 待签名的串，格式见 [buildDataToSign] 。
+	Sign          string // DataToSign 的 HMAC-SHA256 ，小写的十六进制。
+	Authorization string // 最终的 Authorization 头。
+}
+
+// 返回便于阅读的计算过程，依次为时间戳、版本、待签名的串、签名和 Authorization 头。
+// 待签名的串中的换行符按原样输出。
+func (x *SignSteps) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Timestamp: %d (%s)\n", x.Timestamp, time.Unix(x.Timestamp, 0).UTC().Format(time.RFC3339))
+	fmt.Fprintf(&b, "Version: %d\n", x.Version)
+	b.WriteString("\nData to sign:\n")
+	b.WriteString(x.DataToSign)
+	b.WriteString("\n\nSign (HMAC-SHA256): ")
+	b.WriteString(x.Sign)
+	b.WriteString("\nAuthorization: ")
+	b.WriteString(x.Authorization)
+	return b.String()
+}
+
+// 使用给定的时间戳（ Unix 秒）计算签名，返回计算过程。
+// 结果与 [SlimAuthRequest.Build] 写入的 Authorization 头一致，但待签名的串由这里按协议重新拼接，
+// 因为 go-webapi 没有公开这一步。
+func (x *SlimAuthRequest) SignSteps(ctx context.Context, timestamp int64) (*SignSteps, error) {
+	version, err := x.signVersion()
+	if err != nil {
+		return nil, err
+	}
+
+	request, err := x.BuildUnsigned(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	data, typ, err := buildDataToSign(request, timestamp)
+	if typ != slimauth.SignResultType_OK {
+		return nil, x.signError(typ, err)
+	}
+
+	sign := slimauth.HmacSha256([]byte(x.Secret), data)
+	return &SignSteps{
+		Timestamp:  timestamp,
+		Version:    version,
+		DataToSign: string(data),
+		Sign:       sign,
+		Authorization: slimauth.BuildAuthorizationHeader(slimauth.Authorization{
+			Key:       x.Key,
+			Sign:      sign,
+			Timestamp: timestamp,
+			Version:   version,
+		}),
+	}, nil
+}
+
+// 按 SlimAuth 协议拼接待签名的串，各部分以换行符（ \n ）结尾，依次为：
+//   - TIMESTAMP Unix 时间戳。
+//   - METHOD HTTP 请求的 METHOD 。
+//   - PATH 请求的路径，为空时使用“/”。
+//   - QUERY 按参数名称的字节顺序稳定排序后，各参数的值紧密拼接，没有值的参数使用其名称； ~auth 参数被忽略。
+//   - BODY 表单的处理方式同 QUERY ， JSON 使用原文。仅 POST 、 PUT 、 PATCH 请求有此部分。
+//   - 最后一行固定是“END”，其后没有换行符。
+//
+// 与 go-webapi 中未公开的同名方法一致，错误的类型同 [slimauth.SignResult.Type] 。
+// 请求的 body 被读取后替换为可重读的内容。
+func buildDataToSign(r *http.Request, timestamp int64) ([]byte, slimauth.SignResultType, error) {
+	buf := new(bytes.Buffer)
+	buf.WriteString(strconv.FormatInt(timestamp, 10))
+	buf.WriteByte('\n')
+
+	buf.WriteString(r.Method)
+	buf.WriteByte('\n')
+
+	if r.URL.Path == "" {
+		buf.WriteByte('/')
+	} else {
+		buf.WriteString(r.URL.Path)
+	}
+	buf.WriteByte('\n')
+
+	appendSignValues(buf, r.URL.Query(), "~auth")
+
+	if r.Method == http.MethodPost || r.Method == http.MethodPut || r.Method == http.MethodPatch {
+		contentType, ok := r.Header[webapi.HttpHeaderContentType]
+		if !ok {
+			return nil, slimauth.SignResultType_MissingContentType, fmt.Errorf("missing Content-Type")
+		}
+
+		if r.Body == nil {
+			return nil, slimauth.SignResultType_InvalidRequestBody, fmt.Errorf("missing body for %s", contentType[0])
+		}
+
+		body, err := io.ReadAll(r.Body)
+		r.Body.Close()
+		if err != nil {
+			return nil, slimauth.SignResultType_InvalidRequestBody, err
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		switch contentType[0] {
+		case webapi.ContentTypeForm:
+			values, err := url.ParseQuery(string(body))
+			if err != nil {
+				return nil, slimauth.SignResultType_InvalidRequestBody, err
+			}
+			appendSignValues(buf, values, "")
+
+		case webapi.ContentTypeJson:
+			buf.Write(body)
+			buf.WriteByte('\n')
+
+		default:
+			return nil, slimauth.SignResultType_UnsupportedContentType, fmt.Errorf("unsupported Content-Type: %s", contentType[0])
+		}
+	}
+
+	buf.WriteString("END")
+	return buf.Bytes(), slimauth.SignResultType_OK, nil
+}
+
+// 将 values 按 QUERY 部分的规则写入 buf ，名称为 ignore 的参数被忽略。
+func appendSignValues(buf *bytes.Buffer, values url.Values, ignore string) {
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		if k != ignore || ignore == "" {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		for _, v := range values[k] {
+			if v == "" {
+				buf.WriteString(k)
+			} else {
+				buf.WriteString(v)
+			}
+		}
+	}
+	buf.WriteByte('\n')
+}
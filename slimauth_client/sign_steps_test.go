@@ -0,0 +1,85 @@
+package slimauth_client
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	client "github.com/cmstar/go-webapi-client"
+	"github.com/cmstar/go-webapi/slimauth"
+	"github.com/stretchr/testify/require"
+)
+
+// 用例来自 go-webapi 中 slimauth 包的文档。
+func TestSlimAuthRequest_SignSteps(t *testing.T) {
+	const timestamp = 1662439087
+
+	cases := []struct {
+		name string
+		req  SlimAuthRequest
+		data string
+		sign string
+	}{
+		{
+			name: "form",
+			req: SlimAuthRequest{
+				URL:      "http://temp.org/my/path?a&c=3&b=2&z=4&X=%E4%B8%AD%E6%96%87&a=1&b=",
+				BodyType: BodyForm,
+				Form:     []client.KeyValue{{Name: "p1", Value: "11"}, {Name: "p3", Value: "33"}, {Name: "p2", Value: "22"}},
+			},
+			data: "1662439087\nPOST\n/my/path\n中文a12b34\n112233\nEND",
+			sign: "b3baa63839877585cc05495810fb10267317df2fceda2eddcb92a740f78d1ba5",
+		},
+		{
+			name: "get",
+			req:  SlimAuthRequest{URL: "http://temp.org", HttpMethod: http.MethodGet},
+			data: "1662439087\nGET\n/\n\nEND",
+			sign: "980b8715cefc0b98ae2b0788ce849308757554fbe685a05a43e6bc31fb0d0a4c",
+		},
+		{
+			name: "json",
+			req:  SlimAuthRequest{URL: "http://temp.org/p/?x=1&y=2&~auth=x", Param: `{"key":"value"}`},
+			data: "1662439087\nPOST\n/p/\n12\n{\"key\":\"value\"}\nEND",
+			sign: "ce0906df79291d516bb443adbc6099b39f36c006696150202e4e41ffe7dab211",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			r := require.New(t)
+			c.req.Key = "my_key"
+			c.req.Secret = "my_secret"
+
+			steps, err := c.req.SignSteps(context.Background(), timestamp)
+			r.NoError(err)
+			r.Equal(c.data, steps.DataToSign)
+			r.Equal(c.sign, steps.Sign)
+			r.Equal(slimauth.DefaultSignVersion, steps.Version)
+			r.Contains(steps.String(), "Timestamp: 1662439087 (2022-09-06T04:38:07Z)")
+
+			// 与实际发送的请求一致。
+			request, err := c.req.Build(context.Background(), timestamp)
+			r.NoError(err)
+			r.Equal(steps.Authorization, request.Header.Get(slimauth.HttpHeaderAuthorization))
+		})
+	}
+
+	t.Run("errors", func(t *testing.T) {
+		r := require.New(t)
+		req := SlimAuthRequest{URL: "http://temp.org", Param: "{}", SignVersion: 2}
+		_, err := req.SignSteps(context.Background(), timestamp)
+		r.EqualError(err, "unsupported SlimAuth sign version 2")
+		_, err = req.Build(context.Background(), timestamp)
+		r.EqualError(err, "unsupported SlimAuth sign version 2")
+	})
+}
+
+func TestSlimAuthRequest_SignTimestamp(t *testing.T) {
+	r := require.New(t)
+	now := time.Unix(1000, 0)
+
+	r.Equal(int64(1000), (&SlimAuthRequest{}).SignTimestamp(now))
+	r.Equal(int64(700), (&SlimAuthRequest{TimestampOffset: -300}).SignTimestamp(now))
+	r.Equal(int64(42), (&SlimAuthRequest{Timestamp: 42, TimestampOffset: -300}).SignTimestamp(now))
+}
@@ -1,50 +1,104 @@
 package slimauth_client
 
 import (
-	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
-	"io"
 	"net/http"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/container"
 	"fyne.io/fyne/v2/data/binding"
+	"fyne.io/fyne/v2/theme"
 	"fyne.io/fyne/v2/widget"
 	"github.com/cmstar/go-errx"
-	"github.com/cmstar/go-httplib/headers"
 	client "github.com/cmstar/go-webapi-client"
 	"github.com/cmstar/go-webapi/slimauth"
 )
 
 const (
-	_KEY    = "Key"
-	_SECRET = "Secret"
-	_URI    = "Uri"
-	_PARAM  = "Param"
+	_KEY         = "Key"
+	_SECRET      = "Secret"
+	_URI         = "Uri"
+	_HTTP_METHOD = "HttpMethod"
+	_QUERY       = "Query"
+	_HEADER      = "Header"
+	_PARAM       = "Param"
+	_BODY_TYPE   = "BodyType"
+	_FORM        = "Form"
+	_SIGN_VER    = "SignVersion"
+	_TIMESTAMP   = "Timestamp"
+	_TS_OFFSET   = "TimestampOffset"
+	_TRANSPORT   = client.TransportConfigKey
 )
 
+// 可选的签名版本。
+var signVersions = []string{strconv.Itoa(slimauth.DefaultSignVersion)}
+
 type SlimAuthClient struct {
-	key    binding.String
-	sec    binding.String
-	uri    binding.String
-	param  binding.String
-	result binding.String
+	key        binding.String
+	sec        binding.String
+	uri        binding.String
+	httpMethod binding.String
+	query      *client.KeyValueTable
+	header     *client.KeyValueTable
+	param      binding.String
+	bodyType   binding.String
+	form       *client.KeyValueTable
+	signVer    binding.String
+	timestamp  binding.String // 固定的时间戳，为空时使用当前时间。
+	tsOffset   binding.String // 使用当前时间时加上的秒数。
+	signSteps  binding.String // 签名过程面板中展示的内容。
+	result     *client.ResponseView
+	transport  *client.TransportSettings
+	runner     *client.RequestRunner
+	box        fyne.CanvasObject // 见 Box 。
+
+	mu             sync.Mutex                // 保护 vars 和 historyHandler 。
+	vars           map[string]string         // 当前环境的变量，见 [client.VariableSetter] 。
+	historyHandler func(client.HistoryEntry) // 见 [client.HistoryReporter] 。
 }
 
-var _ client.Client = (*SlimAuthClient)(nil)
+var (
+	_ client.Client          = (*SlimAuthClient)(nil)
+	_ client.Executor        = (*SlimAuthClient)(nil)
+	_ client.VariableSetter  = (*SlimAuthClient)(nil)
+	_ client.HistoryReporter = (*SlimAuthClient)(nil)
+	_ client.Submitter       = (*SlimAuthClient)(nil)
+	_ client.Exporter        = (*SlimAuthClient)(nil)
+	_ client.Importer        = (*SlimAuthClient)(nil)
+	_ client.SecretMarker    = (*SlimAuthClient)(nil)
+	_ client.ChangeNotifier  = (*SlimAuthClient)(nil)
+)
 
 // 创建一个 [*SlimAuthClient] 。
 func NewClient() *SlimAuthClient {
-	return &SlimAuthClient{
-		key:    binding.NewString(),
-		sec:    binding.NewString(),
-		uri:    binding.NewString(),
-		param:  binding.NewString(),
-		result: binding.NewString(),
+	x := &SlimAuthClient{
+		key:        binding.NewString(),
+		sec:        binding.NewString(),
+		uri:        binding.NewString(),
+		httpMethod: binding.NewString(),
+		query:      client.NewKeyValueTable(false),
+		header:     client.NewKeyValueTable(false),
+		param:      binding.NewString(),
+		bodyType:   binding.NewString(),
+		form:       client.NewKeyValueTable(false),
+		signVer:    binding.NewString(),
+		timestamp:  binding.NewString(),
+		tsOffset:   binding.NewString(),
+		signSteps:  binding.NewString(),
+		result:     client.NewResponseView(),
+		transport:  client.NewTransportSettings(),
+		runner:     client.NewRequestRunner(),
 	}
+	x.httpMethod.Set(http.MethodPost)
+	x.bodyType.Set(BodyJson)
+	x.signVer.Set(signVersions[0])
+	return x
 }
 
 func (x *SlimAuthClient) Name() string {
@@ -55,17 +109,36 @@ func (x *SlimAuthClient) Title() string {
 	return "SlimAuth"
 }
 
+// 实现 [client.SecretMarker] 。
+func (x *SlimAuthClient) SecretKeys() []string {
+	return []string{_SECRET}
+}
+
 func (x *SlimAuthClient) GetConfig() map[string]any {
 	key, _ := x.key.Get()
 	sec, _ := x.sec.Get()
 	uri, _ := x.uri.Get()
+	httpMethod, _ := x.httpMethod.Get()
 	param, _ := x.param.Get()
+	bodyType, _ := x.bodyType.Get()
+	signVer, _ := x.signVer.Get()
+	timestamp, _ := x.timestamp.Get()
+	tsOffset, _ := x.tsOffset.Get()
 
 	return map[string]any{
-		_KEY:    key,
-		_SECRET: sec,
-		_URI:    uri,
-		_PARAM:  param,
+		_KEY:         key,
+		_SECRET:      sec,
+		_URI:         uri,
+		_HTTP_METHOD: httpMethod,
+		_QUERY:       x.query.GetConfig(),
+		_HEADER:      x.header.GetConfig(),
+		_PARAM:       param,
+		_BODY_TYPE:   bodyType,
+		_FORM:        x.form.GetConfig(),
+		_SIGN_VER:    signVer,
+		_TIMESTAMP:   timestamp,
+		_TS_OFFSET:   tsOffset,
+		_TRANSPORT:   x.transport.GetConfig(),
 	}
 }
 
@@ -73,13 +146,13 @@ func (x *SlimAuthClient) SetConfig(config map[string]any) {
 	read := func(name string) string {
 		v, ok := config[name]
 		if !ok {
-			x.result.Set("missing config key: " + name)
+			x.result.SetText("missing config key: " + name)
 			return ""
 		}
 
 		s, ok := v.(string)
 		if !ok {
-			x.result.Set("config value error, key: " + name)
+			x.result.SetText("config value error, key: " + name)
 			return ""
 		}
 
@@ -90,101 +163,457 @@ func (x *SlimAuthClient) SetConfig(config map[string]any) {
 	x.sec.Set(read(_SECRET))
 	x.uri.Set(read(_URI))
 	x.param.Set(read(_PARAM))
+
+	// 以下几项在早期的配置中没有，缺失时使用默认值，不视为错误。
+	httpMethod, _ := config[_HTTP_METHOD].(string)
+	if httpMethod == "" {
+		httpMethod = http.MethodPost
+	}
+	x.httpMethod.Set(httpMethod)
+	x.query.SetConfig(config[_QUERY])
+	x.header.SetConfig(config[_HEADER])
+
+	bodyType, _ := config[_BODY_TYPE].(string)
+	if bodyType == "" {
+		bodyType = BodyJson
+	}
+	x.bodyType.Set(bodyType)
+	x.form.SetConfig(config[_FORM])
+
+	signVer, _ := config[_SIGN_VER].(string)
+	if signVer == "" {
+		signVer = signVersions[0]
+	}
+	x.signVer.Set(signVer)
+	timestamp, _ := config[_TIMESTAMP].(string)
+	x.timestamp.Set(timestamp)
+	tsOffset, _ := config[_TS_OFFSET].(string)
+	x.tsOffset.Set(tsOffset)
+	x.transport.SetConfig(config[_TRANSPORT])
 }
 
+// 返回界面。界面只创建一次，其中的绑定和监听器也只注册一次，每次切换到此 Client 时不会累积。
 func (x *SlimAuthClient) Box() fyne.CanvasObject {
+	if x.box == nil {
+		x.box = x.makeBox()
+	}
+	return x.box
+}
+
+func (x *SlimAuthClient) makeBox() fyne.CanvasObject {
 	paramInput := widget.NewMultiLineEntry()
 	paramInput.Bind(x.param)
 
+	// GET 请求没有 body ； POST 请求时， JSON 编辑 Param 原文，表单编辑各字段。
+	formBox := x.form.Box()
+	bodyArea := container.NewVBox(
+		client.NewBoundSelect([]string{BodyJson, BodyForm}, x.bodyType),
+		paramInput,
+		formBox,
+	)
+
+	updateBody := binding.NewDataListener(func() {
+		httpMethod, _ := x.httpMethod.Get()
+		bodyType, _ := x.bodyType.Get()
+
+		if httpMethod == http.MethodGet {
+			bodyArea.Hide()
+			return
+		}
+		bodyArea.Show()
+
+		if bodyType == BodyForm {
+			paramInput.Hide()
+			formBox.Show()
+		} else {
+			paramInput.Show()
+			formBox.Hide()
+		}
+	})
+	x.httpMethod.AddListener(updateBody)
+	x.bodyType.AddListener(updateBody)
+
+	// 表格中的 Authorization 和 Content-Type 头会被忽略，给出提示。
+	headerWarningLabel := widget.NewLabel("")
+	headerWarning := container.NewHBox(
+		widget.NewIcon(theme.WarningIcon()),
+		headerWarningLabel,
+	)
+	updateHeaderWarning := func() {
+		var reasons []string
+		seen := make(map[string]bool)
+		for _, kv := range x.header.Items() {
+			reason := IgnoredHeaderReason(kv.Name)
+			if reason != "" && !seen[reason] {
+				seen[reason] = true
+				reasons = append(reasons, reason)
+			}
+		}
+
+		if len(reasons) == 0 {
+			headerWarning.Hide()
+			return
+		}
+		headerWarningLabel.SetText(strings.Join(reasons, "\n"))
+		headerWarning.Show()
+	}
+	x.header.AddChangeListener(updateHeaderWarning)
+	updateHeaderWarning()
+
 	requestForm := &widget.Form{
 		Items: []*widget.FormItem{
 			{Text: "Key", Widget: widget.NewEntryWithData(x.key)},
-			{Text: "Secret", Widget: widget.NewEntryWithData(x.sec)},
+			{Text: "Secret", Widget: client.NewSensitiveEntry(x.sec)},
+			{Text: "HTTP", Widget: client.NewBoundSelect([]string{http.MethodGet, http.MethodPost}, x.httpMethod)},
 			{Text: "URL", Widget: widget.NewEntryWithData(x.uri)},
-			{Text: "Param", Widget: paramInput},
+			{Text: "Query", Widget: x.query.Box()},
+			{Text: "Headers", Widget: container.NewVBox(x.header.Box(), headerWarning)},
+			{Text: "Param", Widget: bodyArea},
 		},
-		OnSubmit: x.onSubmit,
 	}
 
-	responseBox := widget.NewMultiLineEntry()
-	responseBox.Bind(x.result)
-
 	container := container.NewHSplit(
-		container.NewVScroll(requestForm),
-		container.NewVScroll(responseBox),
+		container.NewVScroll(container.NewVBox(requestForm, x.runner.Buttons(x.onSubmit), x.signingBox(), x.transport.Box())),
+		x.result.Box(),
 	)
 
 	return container
 }
 
+// 返回可折叠的签名设置，默认收起。包括签名版本、时间戳，以及展示签名计算过程的面板。
+func (x *SlimAuthClient) signingBox() fyne.CanvasObject {
+	timestampInput := widget.NewEntryWithData(x.timestamp)
+	timestampInput.SetPlaceHolder("Unix seconds, empty for the current time")
+
+	offsetInput := widget.NewEntryWithData(x.tsOffset)
+	offsetInput.SetPlaceHolder("seconds added to the current time, e.g. -300")
+
+	stepsView := widget.NewMultiLineEntry()
+	stepsView.Bind(x.signSteps)
+	stepsView.Wrapping = fyne.TextWrapBreak
+	stepsView.SetMinRowsVisible(10)
+
+	// 使用当前的配置和时间计算一次签名，与立即提交时的签名一致。
+	btnSteps := widget.NewButton("SHOW SIGNING STEPS", func() {
+		x.signSteps.Set(x.formatSignSteps())
+	})
+
+	form := &widget.Form{
+		Items: []*widget.FormItem{
+			{Text: "Version", Widget: client.NewBoundSelect(signVersions, x.signVer)},
+			{Text: "Timestamp", Widget: timestampInput},
+			{Text: "Offset", Widget: offsetInput},
+			{Text: "", Widget: container.NewHBox(btnSteps)},
+		},
+	}
+
+	return widget.NewAccordion(widget.NewAccordionItem("Signing", container.NewVBox(form, stepsView)))
+}
+
+// 返回当前配置的签名计算过程，出错时返回错误信息。
+func (x *SlimAuthClient) formatSignSteps() string {
+	request, err := x.Request()
+	if err != nil {
+		return err.Error()
+	}
+
+	steps, err := request.SignSteps(context.Background(), request.SignTimestamp(time.Now()))
+	if err != nil {
+		return err.Error()
+	}
+	return steps.String()
+}
+
 func (x *SlimAuthClient) onSubmit() {
-	// 采用异步请求。
-	x.result.Set("requesting ...")
+	// 采用异步请求，同一时间只执行一个请求，正在执行时提交操作被忽略。
+	x.runner.Run(func(ctx context.Context) {
+		x.result.SetText("requesting ...")
 
-	go func() {
 		// 发现更新 binding.String 速度太快会来不及反馈到界面上。等一下下。
 		<-time.After(200 * time.Millisecond)
 
-		responseText, err := x.performRequest()
-
+		config := x.GetConfig()
+		request, err := x.Request()
 		if err != nil {
-			x.result.Set(err.Error())
-		} else {
-			x.result.Set(responseText)
+			x.result.SetText(err.Error())
+			return
 		}
-	}()
-}
 
-func (x *SlimAuthClient) performRequest() (responseText string, err error) {
-	defer func() {
-		if err == nil {
-			err = errx.PreserveRecover("", recover())
+		start := time.Now()
+		response, err := x.performRequest(ctx, request)
+
+		switch {
+		case err != nil && ctx.Err() != nil:
+			x.result.SetText(client.CancelledText)
+		case err != nil:
+			x.result.SetText(err.Error())
+		default:
+			x.result.SetResponse(response)
 		}
-	}()
 
-	key, _ := x.key.Get()
-	sec, _ := x.sec.Get()
-	uri, _ := x.uri.Get()
-	param, _ := x.param.Get()
+		x.mu.Lock()
+		handler := x.historyHandler
+		x.mu.Unlock()
+
+		if handler != nil {
+			handler(client.NewHistoryEntry(config, request.URL, request.ParamText(), start, response, err))
+		}
+	})
+}
+
+// 实现 [client.Submitter] 。
+func (x *SlimAuthClient) Submit() {
+	x.onSubmit()
+}
+
+// 实现 [client.ChangeNotifier] 。
+func (x *SlimAuthClient) AddChangeListener(fn func()) {
+	client.AddBindingListener(fn, x.key, x.sec, x.uri, x.httpMethod, x.param, x.bodyType, x.signVer, x.timestamp, x.tsOffset)
+	x.query.AddChangeListener(fn)
+	x.header.AddChangeListener(fn)
+	x.form.AddChangeListener(fn)
+	x.transport.AddChangeListener(fn)
+}
+
+// 实现 [client.HistoryReporter] 。
+func (x *SlimAuthClient) SetHistoryHandler(handler func(entry client.HistoryEntry)) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	x.historyHandler = handler
+}
 
-	// Body must be a JSON.
-	if !json.Valid([]byte(param)) {
-		return "", fmt.Errorf("the request message is not a valid JSON")
+// 使用当前的配置执行请求，用于命令行模式。实现 [client.Executor] 。
+// 响应的状态码不是 2xx 时返回错误。
+func (x *SlimAuthClient) Execute(ctx context.Context) (string, error) {
+	request, err := x.Request()
+	if err != nil {
+		return "", err
 	}
 
-	requestBody := strings.NewReader(param)
-	request, err := http.NewRequest(http.MethodPost, uri, requestBody)
+	response, err := request.Execute(ctx)
 	if err != nil {
 		return "", err
 	}
 
-	request.Header.Set(headers.ContentType, "application/json")
-	signResult := slimauth.AppendSign(request, key, sec, "", time.Now().Unix())
-	if signResult.Type != slimauth.SignResultType_OK {
-		return "", signResult.Cause
+	responseText := response.FormatBody()
+	if !response.IsSuccess() {
+		return "", fmt.Errorf("%s\n%s", response.Status, responseText)
 	}
+	return responseText, nil
+}
 
-	response, err := new(http.Client).Do(request)
+// 实现 [client.Exporter] 。 signed 为 true 时，使用当前时间计算 Authorization 头；
+// 否则导出的请求不含 Authorization 头，签名在 Go 代码中发送请求前计算。
+func (x *SlimAuthClient) ExportRequest(signed bool) (*client.ExportedRequest, error) {
+	request, err := x.Request()
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
-	responseBody, err := io.ReadAll(response.Body)
+	if signed {
+		req, err := request.Build(context.Background(), request.SignTimestamp(time.Now()))
+		if err != nil {
+			return nil, err
+		}
+		return client.NewExportedRequest(req)
+	}
+
+	req, err := request.BuildUnsigned(context.Background())
 	if err != nil {
-		return "", err
+		return nil, err
+	}
+
+	res, err := client.NewExportedRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	// 按界面上的时间戳设置生成对应的表达式。
+	comment := "the current time"
+	timestamp := "time.Now().Unix()"
+	res.GoImports = []string{"time", "github.com/cmstar/go-webapi/slimauth"}
+	switch {
+	case request.Timestamp != 0:
+		comment = "a fixed timestamp"
+		timestamp = strconv.FormatInt(request.Timestamp, 10)
+		res.GoImports = []string{"github.com/cmstar/go-webapi/slimauth"}
+	case request.TimestampOffset != 0:
+		comment = fmt.Sprintf("the current time with an offset of %d seconds", request.TimestampOffset)
+		timestamp = fmt.Sprintf("time.Now().Unix() + (%d)", request.TimestampOffset)
+	}
+
+	res.GoSetup = fmt.Sprintf(`// Sign the request with %s.
+signResult := slimauth.AppendSign(req, %s, %s, "", %s)
+if signResult.Type != slimauth.SignResultType_OK {
+	panic(signResult.Cause)
+}
+`, comment, strconv.Quote(request.Key), strconv.Quote(request.Secret), timestamp)
+	return res, nil
+}
+
+// 实现 [client.Importer] 。请求带有 SLIM-AUTH 签名时匹配。
+func (x *SlimAuthClient) MatchRequest(req *client.CurlRequest) bool {
+	_, err := parseAuthorization(req)
+	return err == nil
+}
+
+// 实现 [client.Importer] 。 Key 从签名中读取， Secret 无法从请求中得到，需在导入后手动填写。
+// 除签名和 body 相关的请求头外，其余请求头导入到 Headers 表格中。
+// POST 请求的 body 可以是 JSON 或 application/x-www-form-urlencoded 的表单； GET 请求的参数保留在 URL 上。
+func (x *SlimAuthClient) ImportRequest(req *client.CurlRequest) (map[string]any, error) {
+	if req.Method != http.MethodGet && req.Method != http.MethodPost {
+		return nil, fmt.Errorf("SlimAuth only supports GET and POST requests, got %s", req.Method)
+	}
+
+	// JSON 优先， curl 的 -d 参数默认的 Content-Type 是表单，但常被用来发送 JSON 。
+	param := req.Body
+	bodyType := BodyJson
+	var form []client.KeyValue
+	switch {
+	case param == "":
+		param = "{}"
+	case json.Valid([]byte(param)):
+	case strings.Contains(req.Header.Get("Content-Type"), "x-www-form-urlencoded"):
+		var err error
+		form, err = client.ParseFormFields(param)
+		if err != nil {
+			return nil, fmt.Errorf("the form body is not valid: %w", err)
+		}
+		param = ""
+		bodyType = BodyForm
+	default:
+		return nil, fmt.Errorf("SlimAuth only supports JSON and form bodies, the body is neither a valid JSON nor a form")
+	}
+
+	// 时间戳每次请求时重新生成，不导入。
+	key := ""
+	signVer := signVersions[0]
+	if auth, err := parseAuthorization(req); err == nil {
+		key = auth.Key
+		signVer = strconv.Itoa(auth.Version)
 	}
 
-	responseText, _ = x.identJson(responseBody)
-	return
+	// 签名也可以放在 ~auth 参数上，每次请求会重新签名，导入时去掉。
+	uri := client.RemoveQueryParams(req.URL, "~auth")
+
+	// 签名、 Content-Type 和 Content-Length 在请求时重新生成，其余请求头按原样导入。
+	header := client.HeaderKeyValues(req.Header, slimauth.HttpHeaderAuthorization, "Content-Type", "Content-Length")
+
+	return map[string]any{
+		_KEY:         key,
+		_SECRET:      "",
+		_URI:         uri,
+		_HTTP_METHOD: req.Method,
+		_QUERY:       []any{},
+		_HEADER:      client.KeyValuesToConfig(header),
+		_PARAM:       param,
+		_BODY_TYPE:   bodyType,
+		_FORM:        client.KeyValuesToConfig(form),
+		_SIGN_VER:    signVer,
+		_TIMESTAMP:   "",
+		_TS_OFFSET:   "",
+	}, nil
 }
 
-// 尝试格式化 JSON 。若给定过的不是合法的 JSON ，返回原值的字符串形式 + ok=false。
-func (x *SlimAuthClient) identJson(v []byte) (res string, ok bool) {
-	const ident = "    "
-	buf := new(bytes.Buffer)
-	err := json.Indent(buf, v, "", ident)
+// 读取请求中的 SLIM-AUTH 签名。
+func parseAuthorization(req *client.CurlRequest) (auth slimauth.Authorization, err error) {
+	request, err := req.HttpRequest()
 	if err != nil {
-		return "", false
+		return auth, err
 	}
-	return buf.String(), true
+
+	// Authorization 头的格式不正确时（如缺少“=”）， ParseAuthorizationHeader 可能 panic 。
+	defer func() {
+		if e := errx.PreserveRecover("", recover()); e != nil {
+			err = e
+		}
+	}()
+
+	return slimauth.ParseAuthorizationHeader(request, slimauth.DefaultAuthScheme)
+}
+
+// 实现 [client.VariableSetter] 。
+func (x *SlimAuthClient) SetVariables(vars map[string]string) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	x.vars = vars
+}
+
+// 根据当前界面上的值创建 [SlimAuthRequest] ，各字段中的变量已被替换。
+// 连接参数有误时返回 error 。
+func (x *SlimAuthClient) Request() (*SlimAuthRequest, error) {
+	x.mu.Lock()
+	vars := x.vars
+	x.mu.Unlock()
+
+	get := func(v binding.String) string {
+		s, _ := v.Get()
+		return client.ExpandVariables(s, vars)
+	}
+
+	expand := func(items []client.KeyValue) []client.KeyValue {
+		for i := range items {
+			items[i].Name = client.ExpandVariables(items[i].Name, vars)
+			items[i].Value = client.ExpandVariables(items[i].Value, vars)
+		}
+		return items
+	}
+
+	// 以下几项来自下拉框，不需要替换变量。
+	httpMethod, _ := x.httpMethod.Get()
+	bodyType, _ := x.bodyType.Get()
+	signVerText, _ := x.signVer.Get()
+
+	signVer, err := strconv.Atoi(signVerText)
+	if err != nil {
+		return nil, fmt.Errorf("the sign version %q is not a number", signVerText)
+	}
+
+	var timestamp, tsOffset int64
+	if s := strings.TrimSpace(get(x.timestamp)); s != "" {
+		timestamp, err = strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("the timestamp %q is not a valid Unix timestamp", s)
+		}
+	}
+
+	if s := strings.TrimSpace(get(x.tsOffset)); s != "" {
+		tsOffset, err = strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("the timestamp offset %q is not a number of seconds", s)
+		}
+	}
+
+	httpClient, err := x.transport.NewHttpClient()
+	if err != nil {
+		return nil, err
+	}
+
+	return &SlimAuthRequest{
+		Key:        get(x.key),
+		Secret:     get(x.sec),
+		URL:        get(x.uri),
+		HttpMethod: httpMethod,
+		Query:      expand(x.query.Items()),
+		Header:     expand(x.header.Items()),
+		BodyType:   bodyType,
+		Param:      get(x.param),
+		Form:       expand(x.form.Items()),
+
+		SignVersion:     signVer,
+		Timestamp:       timestamp,
+		TimestampOffset: tsOffset,
+
+		HttpClient: httpClient,
+	}, nil
+}
+
+func (x *SlimAuthClient) performRequest(ctx context.Context, request *SlimAuthRequest) (response *client.Response, err error) {
+	defer func() {
+		if err == nil {
+			err = errx.PreserveRecover("", recover())
+		}
+	}()
+
+	return request.Execute(ctx)
 }
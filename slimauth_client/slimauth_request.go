@@ -0,0 +1,227 @@
+package slimauth_client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/cmstar/go-httplib/headers"
+	"github.com/cmstar/go-webapi"
+	client "github.com/cmstar/go-webapi-client"
+	"github.com/cmstar/go-webapi/slimauth"
+)
+
+// body 的类型。
+// go-webapi 的签名算法（见 [slimauth.AppendSign] ）只支持 JSON 和表单，因此没有 multipart/form-data 。
+const (
+	BodyJson = "json" // Param 以 application/json 的形式发送。
+	BodyForm = "form" // Form 以 application/x-www-form-urlencoded 的形式发送。
+)
+
+// 描述一个 SlimAuth 协议的请求，不依赖界面，可直接在代码中使用。
+type SlimAuthRequest struct {
+	Key    string // 对应 Authorization 头中的 Key 字段。
+	Secret string // 签名使用的密钥。
+	URL    string // 请求的地址。
+
+	// HTTP 请求的 METHOD ， GET 或 POST 。为空时，默认为 POST 。
+	// GET 请求没有 body ，参数放在 URL 和 Query 上，签名时 BODY 部分被省略。
+	HttpMethod string
+
+	// 追加到 URL 上的 query string 参数，按给定的顺序编码，参与签名。
+	Query []client.KeyValue
+
+	// 附加的请求头，如租户、跟踪 ID 。设置方式见 [client.ApplyHeaders] 。
+	// Authorization 和 Content-Type 头被忽略，原因见 [IgnoredHeaderReason] 。
+	// 目前 SlimAuth 只有一个签名版本，请求头不参与签名。
+	Header []client.KeyValue
+
+	// body 的类型，为 BodyXxx 之一。为空时，默认为 [BodyJson] 。
+	BodyType string
+
+	// 请求的参数，必须是 JSON 。仅在 POST 请求且 [BodyJson] 时使用。
+	Param string
+
+	// POST 请求且 [BodyForm] 时的字段，不能包含文件字段。
+	Form []client.KeyValue
+
+	// 签名算法的版本，写入 Authorization 头的 Version 字段。为 0 时，使用 [slimauth.DefaultSignVersion] ，
+	// 目前 go-webapi 也只支持这一版本。
+	SignVersion int
+
+	// 签名使用的固定时间戳（ Unix 秒），用于复现某一时刻的签名。为 0 时，使用当前时间加上 TimestampOffset 。
+	Timestamp int64
+
+	// 使用当前时间签名时加上的秒数，可以为负数，用于模拟或抵消客户端与服务器之间的时钟偏差。
+	TimestampOffset int64
+
+	// 执行请求所用的 [http.Client] 。若为 nil ，使用 [http.DefaultClient] 。
+	HttpClient *http.Client
+}
+
+// 执行请求。签名的时间戳见 [SlimAuthRequest.SignTimestamp] 。
+// 仅当请求无法发出或响应无法读取时返回 error ，非 2xx 的响应不被视为错误。
+func (x *SlimAuthRequest) Execute(ctx context.Context) (*client.Response, error) {
+	request, err := x.Build(ctx, x.SignTimestamp(time.Now()))
+	if err != nil {
+		return nil, err
+	}
+
+	return client.SendRequest(x.HttpClient, request)
+}
+
+// 返回签名使用的时间戳（ Unix 秒）：给定了 Timestamp 时使用它，否则为 now 加上 TimestampOffset 。
+func (x *SlimAuthRequest) SignTimestamp(now time.Time) int64 {
+	if x.Timestamp != 0 {
+		return x.Timestamp
+	}
+	return now.Unix() + x.TimestampOffset
+}
+
+// 构建 [http.Request] ，并使用给定的时间戳（ Unix 秒）签名。计算过程见 [SlimAuthRequest.SignSteps] 。
+func (x *SlimAuthRequest) Build(ctx context.Context, timestamp int64) (*http.Request, error) {
+	version, err := x.signVersion()
+	if err != nil {
+		return nil, err
+	}
+
+	request, err := x.BuildUnsigned(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	signResult := slimauth.Sign(request, true, x.Secret, timestamp)
+	if signResult.Type != slimauth.SignResultType_OK {
+		return nil, x.signError(signResult.Type, signResult.Cause)
+	}
+
+	auth := slimauth.BuildAuthorizationHeader(slimauth.Authorization{
+		Key:       x.Key,
+		Sign:      signResult.Sign,
+		Timestamp: timestamp,
+		Version:   version,
+	})
+	request.Header.Set(slimauth.HttpHeaderAuthorization, auth)
+	return request, nil
+}
+
+// 返回实际使用的签名版本，不支持的版本返回 error 。
+func (x *SlimAuthRequest) signVersion() (int, error) {
+	switch x.SignVersion {
+	case 0:
+		return slimauth.DefaultSignVersion, nil
+	case slimauth.DefaultSignVersion:
+		return x.SignVersion, nil
+	default:
+		return 0, fmt.Errorf("unsupported SlimAuth sign version %d", x.SignVersion)
+	}
+}
+
+// 将签名失败的原因转换为 error 。
+func (x *SlimAuthRequest) signError(typ slimauth.SignResultType, cause error) error {
+	if typ == slimauth.SignResultType_UnsupportedContentType {
+		return fmt.Errorf("the SlimAuth signature does not support the body type %q: %w", x.BodyType, cause)
+	}
+	return cause
+}
+
+// 构建未签名的 [http.Request] ，即不含 Authorization 头。
+func (x *SlimAuthRequest) BuildUnsigned(ctx context.Context) (*http.Request, error) {
+	uri, err := client.AppendQuery(x.URL, x.Query)
+	if err != nil {
+		return nil, err
+	}
+
+	switch x.HttpMethod {
+	case http.MethodGet:
+		request, err := http.NewRequestWithContext(ctx, http.MethodGet, uri, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		x.applyHeaders(request)
+		return request, nil
+	case "", http.MethodPost:
+	default:
+		return nil, fmt.Errorf("SlimAuth only supports GET and POST requests, got %s", x.HttpMethod)
+	}
+
+	var body []byte
+	var contentType string
+	switch x.BodyType {
+	case "", BodyJson:
+		if !json.Valid([]byte(x.Param)) {
+			return nil, fmt.Errorf("the request message is not a valid JSON")
+		}
+		body = []byte(x.Param)
+		contentType = webapi.ContentTypeJson
+
+	case BodyForm:
+		form, err := client.EncodeForm(x.Form)
+		if err != nil {
+			return nil, err
+		}
+		body = []byte(form)
+		contentType = webapi.ContentTypeForm
+
+	default:
+		return nil, fmt.Errorf("unsupported body type %q", x.BodyType)
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, uri, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	request.Header.Set(headers.ContentType, contentType)
+	x.applyHeaders(request)
+	return request, nil
+}
+
+// 将 Header 设置到请求上，其中的 Authorization 和 Content-Type 头被忽略。
+func (x *SlimAuthRequest) applyHeaders(request *http.Request) {
+	header := make([]client.KeyValue, 0, len(x.Header))
+	for _, kv := range x.Header {
+		if IgnoredHeaderReason(kv.Name) == "" {
+			header = append(header, kv)
+		}
+	}
+	client.ApplyHeaders(request, header)
+}
+
+// 判断 name 是否为存放签名的请求头，即 Authorization ，不区分大小写。
+func IsSignatureHeader(name string) bool {
+	return strings.EqualFold(name, slimauth.HttpHeaderAuthorization)
+}
+
+// 返回 [SlimAuthRequest.Header] 中名为 name 的请求头被忽略的原因，用于界面上的提示；不被忽略时返回空字符串。
+//   - Authorization 头用于存放签名。
+//   - Content-Type 由 BodyType 决定。签名算法只接受与 [webapi.ContentTypeJson] 或 [webapi.ContentTypeForm] 完全相同的值，
+//     如 application/json; charset=utf-8 会使签名失败。
+func IgnoredHeaderReason(name string) string {
+	switch {
+	case IsSignatureHeader(name):
+		return "The Authorization header is replaced by the signature."
+	case strings.EqualFold(name, headers.ContentType):
+		return "The Content-Type header is set by the body type."
+	default:
+		return ""
+	}
+}
+
+// 返回请求参数的描述，用于 [client.HistoryEntry] 。
+// GET 请求时为 Query ， [BodyForm] 时为 Form ，格式见 [client.FormatFormFields] 。
+func (x *SlimAuthRequest) ParamText() string {
+	switch {
+	case x.HttpMethod == http.MethodGet:
+		return client.FormatFormFields(x.Query)
+	case x.BodyType == BodyForm:
+		return client.FormatFormFields(x.Form)
+	default:
+		return x.Param
+	}
+}
@@ -0,0 +1,403 @@
+package slimauth_client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/cmstar/go-logx"
+	"github.com/cmstar/go-webapi"
+	client "github.com/cmstar/go-webapi-client"
+	"github.com/cmstar/go-webapi/slimauth"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	_TEST_KEY    = "my-app"
+	_TEST_SECRET = "my-secret"
+)
+
+func newTestServer() *httptest.Server {
+	handler := slimauth.NewSlimAuthApiHandler(slimauth.SlimAuthApiHandlerOption{
+		SecretFinder: func(accessKey string) string {
+			if accessKey == _TEST_KEY {
+				return _TEST_SECRET
+			}
+			return ""
+		},
+	})
+
+	handler.RegisterMethod(webapi.ApiMethod{
+		Name: "Test",
+		Value: reflect.ValueOf(func(req struct{ S1, S2 string }) string {
+			return req.S1 + "," + req.S2
+		}),
+	})
+
+	logger := logx.NopLogger
+	handlerFunc := webapi.CreateHandlerFunc(handler, logx.NewSingleLoggerLogFinder(logger))
+	return httptest.NewServer(http.HandlerFunc(handlerFunc))
+}
+
+func TestSlimAuthRequest_Execute(t *testing.T) {
+	ts := newTestServer()
+	defer ts.Close()
+
+	t.Run("ok", func(t *testing.T) {
+		r := require.New(t)
+		req := &SlimAuthRequest{
+			Key:    _TEST_KEY,
+			Secret: _TEST_SECRET,
+			URL:    ts.URL + "?Test",
+			Param:  `{"S1":"a","S2":"b"}`,
+		}
+
+		res, err := req.Execute(context.Background())
+		r.NoError(err)
+		r.Equal(200, res.StatusCode)
+		r.Equal("200 OK", res.Status)
+		r.Contains(res.Header.Get("Content-Type"), "application/json")
+		r.JSONEq(`{"Code":0,"Message":"","Data":"a,b"}`, string(res.Body))
+		r.Greater(res.Duration.Nanoseconds(), int64(0))
+	})
+
+	t.Run("bad-secret", func(t *testing.T) {
+		r := require.New(t)
+		req := &SlimAuthRequest{
+			Key:    _TEST_KEY,
+			Secret: "wrong",
+			URL:    ts.URL + "?Test",
+			Param:  `{}`,
+		}
+
+		res, err := req.Execute(context.Background())
+		r.NoError(err)
+		r.Contains(string(res.Body), `"Code":400`)
+	})
+
+	t.Run("get", func(t *testing.T) {
+		r := require.New(t)
+		req := &SlimAuthRequest{
+			Key:        _TEST_KEY,
+			Secret:     _TEST_SECRET,
+			URL:        ts.URL + "?Test&S2=b",
+			HttpMethod: http.MethodGet,
+			Query:      []client.KeyValue{{Name: "S1", Value: "a &中"}},
+			Param:      "ignored",
+		}
+		r.Equal("S1=a+%26%E4%B8%AD", req.ParamText())
+
+		request, err := req.BuildUnsigned(context.Background())
+		r.NoError(err)
+		r.Equal(http.MethodGet, request.Method)
+		r.Equal("Test&S2=b&S1=a+%26%E4%B8%AD", request.URL.RawQuery)
+		r.Nil(request.Body)
+
+		res, err := req.Execute(context.Background())
+		r.NoError(err)
+		r.JSONEq(`{"Code":0,"Message":"","Data":"a &中,b"}`, string(res.Body))
+	})
+
+	t.Run("header", func(t *testing.T) {
+		r := require.New(t)
+		req := &SlimAuthRequest{
+			Key:    _TEST_KEY,
+			Secret: _TEST_SECRET,
+			URL:    ts.URL + "?Test",
+			Param:  `{"S1":"a","S2":"b"}`,
+			Header: []client.KeyValue{
+				{Name: "X-Tenant", Value: "t1"},
+				{Name: "Accept-Language", Value: "zh-CN"},
+				{Name: "authorization", Value: "ignored"},
+				{Name: "content-type", Value: "application/json; charset=utf-8"},
+			},
+		}
+
+		request, err := req.BuildUnsigned(context.Background())
+		r.NoError(err)
+		r.Equal("t1", request.Header.Get("X-Tenant"))
+		r.Equal("zh-CN", request.Header.Get("Accept-Language"))
+		r.Empty(request.Header.Get(slimauth.HttpHeaderAuthorization))
+		r.Equal(webapi.ContentTypeJson, request.Header.Get("Content-Type"))
+		r.Equal("The Content-Type header is set by the body type.", IgnoredHeaderReason("CONTENT-TYPE"))
+		r.Empty(IgnoredHeaderReason("X-Tenant"))
+
+		res, err := req.Execute(context.Background())
+		r.NoError(err)
+		r.JSONEq(`{"Code":0,"Message":"","Data":"a,b"}`, string(res.Body))
+	})
+
+	t.Run("bad-method", func(t *testing.T) {
+		req := &SlimAuthRequest{URL: ts.URL, HttpMethod: http.MethodPut, Param: "{}"}
+		_, err := req.Execute(context.Background())
+		require.EqualError(t, err, "SlimAuth only supports GET and POST requests, got PUT")
+	})
+
+	t.Run("form", func(t *testing.T) {
+		r := require.New(t)
+		req := &SlimAuthRequest{
+			Key:      _TEST_KEY,
+			Secret:   _TEST_SECRET,
+			URL:      ts.URL + "?Test",
+			BodyType: BodyForm,
+			Form:     []client.KeyValue{{Name: "S2", Value: "b b"}, {Name: "S1", Value: "a&"}},
+		}
+		r.Equal("S2=b+b&S1=a%26", req.ParamText())
+
+		res, err := req.Execute(context.Background())
+		r.NoError(err)
+		r.JSONEq(`{"Code":0,"Message":"","Data":"a&,b b"}`, string(res.Body))
+	})
+
+	// go-webapi 不支持对 multipart/form-data 签名，不提供该 body 类型，也不能上传文件。
+	t.Run("multipart", func(t *testing.T) {
+		r := require.New(t)
+		req := &SlimAuthRequest{URL: ts.URL, BodyType: "multipart"}
+		_, err := req.Execute(context.Background())
+		r.EqualError(err, `unsupported body type "multipart"`)
+
+		req = &SlimAuthRequest{URL: ts.URL, BodyType: BodyForm, Form: []client.KeyValue{{Name: "f", Value: "a.txt", File: true}}}
+		_, err = req.Execute(context.Background())
+		r.EqualError(err, `the file field "f" requires a multipart body`)
+	})
+
+	t.Run("invalid-json", func(t *testing.T) {
+		req := &SlimAuthRequest{URL: ts.URL, Param: `{`}
+		_, err := req.Execute(context.Background())
+		require.EqualError(t, err, "the request message is not a valid JSON")
+	})
+}
+
+func TestSlimAuthClient_Request(t *testing.T) {
+	r := require.New(t)
+	c := NewClient()
+
+	// 早期的配置没有 HttpMethod 、 Query 、 Header 、 BodyType 、 Form 和签名的设置。
+	c.SetConfig(map[string]any{_KEY: "k", _SECRET: "s", _URI: "u", _PARAM: "{}"})
+	req, err := c.Request()
+	r.NoError(err)
+	r.Equal(http.MethodPost, req.HttpMethod)
+	r.Empty(req.Query)
+	r.Empty(req.Header)
+	r.Equal(BodyJson, req.BodyType)
+	r.Empty(req.Form)
+	r.Equal(slimauth.DefaultSignVersion, req.SignVersion)
+	r.Zero(req.Timestamp)
+	r.Zero(req.TimestampOffset)
+
+	c.SetConfig(map[string]any{
+		_KEY: "k", _SECRET: "s", _URI: "u", _PARAM: "",
+		_HTTP_METHOD: http.MethodGet,
+		_QUERY:       []any{map[string]any{"Name": "S1", "Value": "{{v}}"}},
+		_HEADER:      []any{map[string]any{"Name": "X-Tenant", "Value": "{{v}}"}},
+	})
+	c.SetVariables(map[string]string{"v": "a"})
+	req, err = c.Request()
+	r.NoError(err)
+	r.Equal(http.MethodGet, req.HttpMethod)
+	r.Equal([]client.KeyValue{{Name: "S1", Value: "a"}}, req.Query)
+	r.Equal([]client.KeyValue{{Name: "X-Tenant", Value: "a"}}, req.Header)
+	r.Equal([]any{map[string]any{"Name": "X-Tenant", "Value": "{{v}}"}}, c.GetConfig()[_HEADER])
+
+	c.SetConfig(map[string]any{
+		_KEY: "k", _SECRET: "s", _URI: "u", _PARAM: "",
+		_BODY_TYPE: BodyForm,
+		_FORM:      []any{map[string]any{"Name": "S1", "Value": "{{v}}"}},
+	})
+	req, err = c.Request()
+	r.NoError(err)
+	r.Equal(BodyForm, req.BodyType)
+	r.Equal([]client.KeyValue{{Name: "S1", Value: "a"}}, req.Form)
+
+	c.SetConfig(map[string]any{
+		_KEY: "k", _SECRET: "s", _URI: "u", _PARAM: "{}",
+		_SIGN_VER: "1", _TIMESTAMP: " 1662439087 ", _TS_OFFSET: "-300",
+	})
+	req, err = c.Request()
+	r.NoError(err)
+	r.Equal(int64(1662439087), req.Timestamp)
+	r.Equal(int64(-300), req.TimestampOffset)
+
+	c.SetConfig(map[string]any{_KEY: "k", _SECRET: "s", _URI: "u", _PARAM: "{}", _TIMESTAMP: "now"})
+	_, err = c.Request()
+	r.EqualError(err, `the timestamp "now" is not a valid Unix timestamp`)
+
+	c.SetConfig(map[string]any{_KEY: "k", _SECRET: "s", _URI: "u", _PARAM: "{}", _TS_OFFSET: "5m"})
+	_, err = c.Request()
+	r.EqualError(err, `the timestamp offset "5m" is not a number of seconds`)
+}
+
+func TestSlimAuthClient_ExportRequest(t *testing.T) {
+	ts := newTestServer()
+	defer ts.Close()
+
+	c := NewClient()
+	c.SetConfig(map[string]any{
+		_KEY:    _TEST_KEY,
+		_SECRET: _TEST_SECRET,
+		_URI:    ts.URL + "?Test",
+		_PARAM:  `{"S1":"a","S2":"b"}`,
+	})
+
+	t.Run("signed", func(t *testing.T) {
+		r := require.New(t)
+		req, err := c.ExportRequest(true)
+		r.NoError(err)
+		r.Empty(req.GoSetup)
+
+		// The exported request can be replayed as is.
+		request, err := http.NewRequest(req.Method, req.URL, strings.NewReader(req.Body))
+		r.NoError(err)
+		request.Header = req.Header
+
+		auth, err := slimauth.ParseAuthorizationHeader(request, slimauth.DefaultAuthScheme)
+		r.NoError(err)
+		r.Equal(_TEST_KEY, auth.Key)
+
+		res, err := client.SendRequest(nil, request)
+		r.NoError(err)
+		r.JSONEq(`{"Code":0,"Message":"","Data":"a,b"}`, string(res.Body))
+	})
+
+	t.Run("unsigned", func(t *testing.T) {
+		r := require.New(t)
+		req, err := c.ExportRequest(false)
+		r.NoError(err)
+		r.Empty(req.Header.Get(slimauth.HttpHeaderAuthorization))
+		r.Contains(req.GoSetup, `slimauth.AppendSign(req, "my-app", "my-secret", "", time.Now().Unix())`)
+		r.Contains(req.GoImports, "github.com/cmstar/go-webapi/slimauth")
+	})
+
+	t.Run("timestamp", func(t *testing.T) {
+		r := require.New(t)
+		c := NewClient()
+		c.SetConfig(map[string]any{_KEY: "k", _SECRET: "s", _URI: ts.URL + "?Test", _PARAM: "{}", _TS_OFFSET: "-300"})
+		req, err := c.ExportRequest(false)
+		r.NoError(err)
+		r.Contains(req.GoSetup, `slimauth.AppendSign(req, "k", "s", "", time.Now().Unix() + (-300))`)
+
+		c.SetConfig(map[string]any{_KEY: "k", _SECRET: "s", _URI: ts.URL + "?Test", _PARAM: "{}", _TIMESTAMP: "1662439087"})
+		req, err = c.ExportRequest(false)
+		r.NoError(err)
+		r.Contains(req.GoSetup, `slimauth.AppendSign(req, "k", "s", "", 1662439087)`)
+		r.NotContains(req.GoImports, "time")
+
+		req, err = c.ExportRequest(true)
+		r.NoError(err)
+		r.Contains(req.Header.Get(slimauth.HttpHeaderAuthorization), "Timestamp=1662439087")
+	})
+}
+
+func TestSlimAuthClient_ImportRequest(t *testing.T) {
+	t.Run("signed", func(t *testing.T) {
+		r := require.New(t)
+
+		// Round trip: export a signed request as curl, then import it back.
+		c := NewClient()
+		c.SetConfig(map[string]any{
+			_KEY:    _TEST_KEY,
+			_SECRET: _TEST_SECRET,
+			_URI:    "http://localhost/?Test",
+			_PARAM:  `{"S1":"a"}`,
+		})
+		exported, err := c.ExportRequest(true)
+		r.NoError(err)
+		snippet, err := client.ExportSnippet(client.ExportFormatCurl, exported)
+		r.NoError(err)
+
+		req, err := client.ParseCurl(snippet)
+		r.NoError(err)
+		r.True(c.MatchRequest(req))
+
+		conf, err := c.ImportRequest(req)
+		r.NoError(err)
+		r.Equal(map[string]any{
+			_KEY:         _TEST_KEY,
+			_SECRET:      "",
+			_URI:         "http://localhost/?Test",
+			_HTTP_METHOD: http.MethodPost,
+			_QUERY:       []any{},
+			_HEADER:      []any{},
+			_PARAM:       `{"S1":"a"}`,
+			_BODY_TYPE:   BodyJson,
+			_FORM:        []any{},
+			_SIGN_VER:    "1",
+			_TIMESTAMP:   "",
+			_TS_OFFSET:   "",
+		}, conf)
+	})
+
+	t.Run("auth-param", func(t *testing.T) {
+		r := require.New(t)
+		req, err := client.ParseCurl(`curl 'http://localhost/?Method&b=2&a=1&~auth=SLIM-AUTH%20Key=k,Sign=s' -d ''`)
+		r.NoError(err)
+
+		c := NewClient()
+		r.True(c.MatchRequest(req))
+
+		conf, err := c.ImportRequest(req)
+		r.NoError(err)
+		r.Equal("k", conf[_KEY])
+		r.Equal("http://localhost/?Method&b=2&a=1", conf[_URI])
+		r.Equal("{}", conf[_PARAM])
+	})
+
+	t.Run("unsigned", func(t *testing.T) {
+		r := require.New(t)
+		req, err := client.ParseCurl(`curl http://localhost/ -H 'Authorization: SLIM-AUTH Key' -d '{}'`)
+		r.NoError(err)
+
+		c := NewClient()
+		r.False(c.MatchRequest(req))
+
+		conf, err := c.ImportRequest(req)
+		r.NoError(err)
+		r.Equal("", conf[_KEY])
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		r := require.New(t)
+		c := NewClient()
+
+		req, err := client.ParseCurl(`curl -X PUT http://localhost/ -d '{}'`)
+		r.NoError(err)
+		_, err = c.ImportRequest(req)
+		r.EqualError(err, "SlimAuth only supports GET and POST requests, got PUT")
+
+		req, err = client.ParseCurl(`curl http://localhost/ -H 'Content-Type: text/plain' -d a=1`)
+		r.NoError(err)
+		_, err = c.ImportRequest(req)
+		r.EqualError(err, "SlimAuth only supports JSON and form bodies, the body is neither a valid JSON nor a form")
+	})
+
+	t.Run("get", func(t *testing.T) {
+		r := require.New(t)
+		req, err := client.ParseCurl(`curl 'http://localhost/?Test&S1=a' -H 'Authorization: SLIM-AUTH Key=k, Sign=s, Timestamp=1' -H 'X-Tenant: t1'`)
+		r.NoError(err)
+
+		conf, err := NewClient().ImportRequest(req)
+		r.NoError(err)
+		r.Equal("k", conf[_KEY])
+		r.Equal(http.MethodGet, conf[_HTTP_METHOD])
+		r.Equal("http://localhost/?Test&S1=a", conf[_URI])
+		r.Equal([]any{map[string]any{"Name": "X-Tenant", "Value": "t1"}}, conf[_HEADER])
+	})
+
+	t.Run("form", func(t *testing.T) {
+		r := require.New(t)
+		req, err := client.ParseCurl(`curl http://localhost/ -d 'S2=b&S1=a'`)
+		r.NoError(err)
+
+		conf, err := NewClient().ImportRequest(req)
+		r.NoError(err)
+		r.Equal(BodyForm, conf[_BODY_TYPE])
+		r.Equal("", conf[_PARAM])
+		r.Equal([]any{
+			map[string]any{"Name": "S2", "Value": "b"},
+			map[string]any{"Name": "S1", "Value": "a"},
+		}, conf[_FORM])
+	})
+}
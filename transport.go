@@ -0,0 +1,265 @@
+package client
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"sync"
+	"time"
+)
+
+// 未指定超时时间时，使用的默认值。
+const DefaultRequestTimeout = 60 * time.Second
+
+// 配置中用于存放 [TransportOption] 的 key 。
+const TransportConfigKey = "Transport"
+
+// 存储全局默认 [TransportOption] 的文件，位于配置的根目录。
+const transportFileName = "transport.json"
+
+// 描述发送 HTTP 请求时使用的连接参数，由 [NewHttpClient] 使用。
+// 各 [Client] 可以在自己的配置中单独指定，未指定的部分使用 [DefaultTransportOption] 。
+type TransportOption struct {
+	// 请求的超时时间，包括连接、发送和读取响应的全过程。为 0 时使用默认值。
+	Timeout time.Duration
+
+	// 代理服务器的地址，支持 http:// 、 https:// 和 socks5:// 。
+	// 为空时，使用环境变量 HTTP_PROXY 、 HTTPS_PROXY 、 NO_PROXY 的设置。
+	Proxy string
+
+	// 是否跳过 TLS 证书的校验，用于访问使用自签名证书的服务器。
+	InsecureSkipVerify bool
+
+	// PEM 格式的 CA 证书文件的路径，其中的证书会被追加到系统的证书池中。
+	CACertFile string
+
+	// PEM 格式的客户端证书和私钥文件的路径，用于双向 TLS 认证。需同时给定。
+	ClientCertFile string
+	ClientKeyFile  string
+}
+
+// 返回一个新的 [TransportOption] ，其中为空的字段使用 def 中的值填充。
+// 由于 bool 类型无法区分“未指定”， InsecureSkipVerify 只要有一方为 true 即为 true 。
+func (x TransportOption) Merge(def TransportOption) TransportOption {
+	if x.Timeout == 0 {
+		x.Timeout = def.Timeout
+	}
+
+	if x.Proxy == "" {
+		x.Proxy = def.Proxy
+	}
+
+	x.InsecureSkipVerify = x.InsecureSkipVerify || def.InsecureSkipVerify
+
+	if x.CACertFile == "" {
+		x.CACertFile = def.CACertFile
+	}
+
+	if x.ClientCertFile == "" && x.ClientKeyFile == "" {
+		x.ClientCertFile = def.ClientCertFile
+		x.ClientKeyFile = def.ClientKeyFile
+	}
+
+	return x
+}
+
+// 转换为可存储在配置中的形式。 Timeout 以 [time.Duration.String] 的格式存储，为 0 时存储为空字符串。
+func (x TransportOption) ToConfig() map[string]any {
+	timeout := ""
+	if x.Timeout != 0 {
+		timeout = x.Timeout.String()
+	}
+
+	return map[string]any{
+		"Timeout":            timeout,
+		"Proxy":              x.Proxy,
+		"InsecureSkipVerify": x.InsecureSkipVerify,
+		"CACertFile":         x.CACertFile,
+		"ClientCertFile":     x.ClientCertFile,
+		"ClientKeyFile":      x.ClientKeyFile,
+	}
+}
+
+// 从配置中读取 [TransportOption] ，是 [TransportOption.ToConfig] 的逆操作。
+// config 为 nil 或缺少部分字段时，对应字段保留零值。
+func TransportOptionFromConfig(config map[string]any) (TransportOption, error) {
+	var res TransportOption
+
+	readString := func(name string) (string, error) {
+		v, ok := config[name]
+		if !ok || v == nil {
+			return "", nil
+		}
+
+		s, ok := v.(string)
+		if !ok {
+			return "", fmt.Errorf("transport config value error, key: %s", name)
+		}
+		return s, nil
+	}
+
+	timeout, err := readString("Timeout")
+	if err != nil {
+		return res, err
+	}
+
+	if timeout != "" {
+		res.Timeout, err = time.ParseDuration(timeout)
+		if err != nil {
+			return res, fmt.Errorf("transport config value error, key: Timeout: %w", err)
+		}
+	}
+
+	if v, ok := config["InsecureSkipVerify"]; ok && v != nil {
+		b, ok := v.(bool)
+		if !ok {
+			return res, fmt.Errorf("transport config value error, key: InsecureSkipVerify")
+		}
+		res.InsecureSkipVerify = b
+	}
+
+	for name, p := range map[string]*string{
+		"Proxy":          &res.Proxy,
+		"CACertFile":     &res.CACertFile,
+		"ClientCertFile": &res.ClientCertFile,
+		"ClientKeyFile":  &res.ClientKeyFile,
+	} {
+		*p, err = readString(name)
+		if err != nil {
+			return res, err
+		}
+	}
+
+	return res, nil
+}
+
+var (
+	defaultTransportMu     sync.RWMutex
+	defaultTransportOption TransportOption
+)
+
+// 返回全局默认的 [TransportOption] 。
+func DefaultTransportOption() TransportOption {
+	defaultTransportMu.RLock()
+	defer defaultTransportMu.RUnlock()
+	return defaultTransportOption
+}
+
+// 设置全局默认的 [TransportOption] ，它会被 [NewHttpClient] 用于填充未指定的字段。
+func SetDefaultTransportOption(op TransportOption) {
+	defaultTransportMu.Lock()
+	defer defaultTransportMu.Unlock()
+	defaultTransportOption = op
+}
+
+// 根据给定的参数创建 [http.Client] ， op 中未指定的字段使用 [DefaultTransportOption] 填充，
+// 超时时间仍未指定时，使用 [DefaultRequestTimeout] 。各 [Client] 的实现应使用此方法创建 [http.Client] 。
+func NewHttpClient(op TransportOption) (*http.Client, error) {
+	op = op.Merge(DefaultTransportOption())
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+
+	if op.Proxy != "" {
+		proxyUrl, err := url.Parse(op.Proxy)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy: %w", err)
+		}
+
+		switch proxyUrl.Scheme {
+		case "http", "https", "socks5":
+		default:
+			return nil, fmt.Errorf("unsupported proxy scheme %q", proxyUrl.Scheme)
+		}
+		transport.Proxy = http.ProxyURL(proxyUrl)
+	}
+
+	tlsConfig := &tls.Config{
+		InsecureSkipVerify: op.InsecureSkipVerify,
+	}
+
+	if op.CACertFile != "" {
+		pem, err := os.ReadFile(op.CACertFile)
+		if err != nil {
+			return nil, fmt.Errorf("cannot read the CA certificate: %w", err)
+		}
+
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificate found in %s", op.CACertFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if op.ClientCertFile != "" || op.ClientKeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(op.ClientCertFile, op.ClientKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("cannot load the client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	transport.TLSClientConfig = tlsConfig
+
+	timeout := op.Timeout
+	if timeout == 0 {
+		timeout = DefaultRequestTimeout
+	}
+
+	return &http.Client{
+		Transport: transport,
+		Timeout:   timeout,
+	}, nil
+}
+
+// 读取全局默认的 [TransportOption] 。文件不存在时返回零值。
+func (x *ConfigManager) LoadTransportDefaults() (TransportOption, error) {
+	p := path.Join(x.rootPath, transportFileName)
+	content, err := os.ReadFile(p)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return TransportOption{}, nil
+		}
+		return TransportOption{}, x.newError("load-transport", transportFileName, err)
+	}
+
+	var config map[string]any
+	err = json.Unmarshal(content, &config)
+	if err != nil {
+		return TransportOption{}, x.newError("load-transport", transportFileName, wrapCorrupt(err))
+	}
+
+	op, err := TransportOptionFromConfig(config)
+	if err != nil {
+		return TransportOption{}, x.newError("load-transport", transportFileName, wrapCorrupt(err))
+	}
+	return op, nil
+}
+
+// 保存全局默认的 [TransportOption] 。
+func (x *ConfigManager) SaveTransportDefaults(op TransportOption) error {
+	err := os.MkdirAll(x.rootPath, 0755)
+	if err != nil && !os.IsExist(err) {
+		return x.newError("save-transport", transportFileName, err)
+	}
+
+	content, err := json.MarshalIndent(op.ToConfig(), "", "    ")
+	if err != nil {
+		return x.newError("save-transport", transportFileName, err)
+	}
+
+	p := path.Join(x.rootPath, transportFileName)
+	err = os.WriteFile(p, content, 0644)
+	if err != nil {
+		return x.newError("save-transport", transportFileName, err)
+	}
+	return nil
+}
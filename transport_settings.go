@@ -0,0 +1,119 @@
+package client
+
+import (
+	"net/http"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/data/binding"
+	"fyne.io/fyne/v2/widget"
+)
+
+// 编辑 [TransportOption] 的界面组件，供各 [Client] 的实现嵌入到自己的界面中，
+// 配置以 [TransportOption.ToConfig] 的格式读写。
+type TransportSettings struct {
+	timeout    binding.String
+	proxy      binding.String
+	insecure   binding.Bool
+	caCert     binding.String
+	clientCert binding.String
+	clientKey  binding.String
+}
+
+// 创建一个 [*TransportSettings] 。
+func NewTransportSettings() *TransportSettings {
+	return &TransportSettings{
+		timeout:    binding.NewString(),
+		proxy:      binding.NewString(),
+		insecure:   binding.NewBool(),
+		caCert:     binding.NewString(),
+		clientCert: binding.NewString(),
+		clientKey:  binding.NewString(),
+	}
+}
+
+// 返回编辑各字段的表单项。留空的字段使用全局默认值。
+func (x *TransportSettings) FormItems() []*widget.FormItem {
+	timeoutInput := widget.NewEntryWithData(x.timeout)
+	timeoutInput.SetPlaceHolder("e.g. 30s, empty for default")
+
+	proxyInput := widget.NewEntryWithData(x.proxy)
+	proxyInput.SetPlaceHolder("http://, https:// or socks5://")
+
+	return []*widget.FormItem{
+		{Text: "Timeout", Widget: timeoutInput},
+		{Text: "Proxy", Widget: proxyInput},
+		{Text: "Skip TLS verify", Widget: widget.NewCheckWithData("", x.insecure)},
+		{Text: "CA cert file", Widget: widget.NewEntryWithData(x.caCert)},
+		{Text: "Client cert file", Widget: widget.NewEntryWithData(x.clientCert)},
+		{Text: "Client key file", Widget: widget.NewEntryWithData(x.clientKey)},
+	}
+}
+
+// 返回可折叠的界面，默认收起。
+func (x *TransportSettings) Box() fyne.CanvasObject {
+	form := widget.NewForm(x.FormItems()...)
+	return widget.NewAccordion(widget.NewAccordionItem("Transport", form))
+}
+
+// 添加各字段被修改后的回调。实现 [ChangeNotifier] 。
+func (x *TransportSettings) AddChangeListener(fn func()) {
+	AddBindingListener(fn, x.timeout, x.proxy, x.insecure, x.caCert, x.clientCert, x.clientKey)
+}
+
+// 读取当前界面的配置，格式同 [TransportOption.ToConfig] ， Timeout 保留界面上的原文。
+func (x *TransportSettings) GetConfig() map[string]any {
+	timeout, _ := x.timeout.Get()
+	proxy, _ := x.proxy.Get()
+	insecure, _ := x.insecure.Get()
+	caCert, _ := x.caCert.Get()
+	clientCert, _ := x.clientCert.Get()
+	clientKey, _ := x.clientKey.Get()
+
+	return map[string]any{
+		"Timeout":            timeout,
+		"Proxy":              proxy,
+		"InsecureSkipVerify": insecure,
+		"CACertFile":         caCert,
+		"ClientCertFile":     clientCert,
+		"ClientKeyFile":      clientKey,
+	}
+}
+
+// 设置当前界面的配置。 config 通常是 Client 配置中 [TransportConfigKey] 对应的值，
+// 为 nil 或类型不正确时，各字段被清空，即全部使用默认值。
+func (x *TransportSettings) SetConfig(config any) {
+	m, _ := config.(map[string]any)
+
+	str := func(name string) string {
+		s, _ := m[name].(string)
+		return s
+	}
+
+	insecure, _ := m["InsecureSkipVerify"].(bool)
+
+	x.timeout.Set(str("Timeout"))
+	x.proxy.Set(str("Proxy"))
+	x.insecure.Set(insecure)
+	x.caCert.Set(str("CACertFile"))
+	x.clientCert.Set(str("ClientCertFile"))
+	x.clientKey.Set(str("ClientKeyFile"))
+}
+
+// 返回当前界面上的 [TransportOption] 。
+func (x *TransportSettings) Option() (TransportOption, error) {
+	return TransportOptionFromConfig(x.GetConfig())
+}
+
+// 将给定的 [TransportOption] 展示到界面上。
+func (x *TransportSettings) SetOption(op TransportOption) {
+	x.SetConfig(op.ToConfig())
+}
+
+// 使用当前界面上的参数创建 [http.Client] ，见 [NewHttpClient] 。
+func (x *TransportSettings) NewHttpClient() (*http.Client, error) {
+	op, err := x.Option()
+	if err != nil {
+		return nil, err
+	}
+	return NewHttpClient(op)
+}
@@ -0,0 +1,139 @@
+package client
+
+import (
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransportOption_Config(t *testing.T) {
+	r := require.New(t)
+
+	op := TransportOption{
+		Timeout:            1500 * time.Millisecond,
+		Proxy:              "socks5://127.0.0.1:1080",
+		InsecureSkipVerify: true,
+		CACertFile:         "ca.pem",
+		ClientCertFile:     "cert.pem",
+		ClientKeyFile:      "key.pem",
+	}
+	res, err := TransportOptionFromConfig(op.ToConfig())
+	r.NoError(err)
+	r.Equal(op, res)
+
+	res, err = TransportOptionFromConfig(nil)
+	r.NoError(err)
+	r.Equal(TransportOption{}, res)
+	r.Equal("", TransportOption{}.ToConfig()["Timeout"])
+
+	_, err = TransportOptionFromConfig(map[string]any{"Timeout": "abc"})
+	r.Error(err)
+
+	_, err = TransportOptionFromConfig(map[string]any{"InsecureSkipVerify": "true"})
+	r.Error(err)
+}
+
+func TestTransportOption_Merge(t *testing.T) {
+	def := TransportOption{
+		Timeout:        time.Second,
+		Proxy:          "http://proxy",
+		CACertFile:     "ca.pem",
+		ClientCertFile: "cert.pem",
+		ClientKeyFile:  "key.pem",
+	}
+
+	require.Equal(t, def, TransportOption{}.Merge(def))
+
+	op := TransportOption{Timeout: time.Minute, InsecureSkipVerify: true, ClientCertFile: "my.pem"}
+	require.Equal(t, TransportOption{
+		Timeout:            time.Minute,
+		Proxy:              "http://proxy",
+		InsecureSkipVerify: true,
+		CACertFile:         "ca.pem",
+		ClientCertFile:     "my.pem",
+	}, op.Merge(def))
+}
+
+func TestNewHttpClient(t *testing.T) {
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/slow" {
+			time.Sleep(200 * time.Millisecond)
+		}
+		w.Write([]byte("ok"))
+	}))
+	defer ts.Close()
+
+	get := func(op TransportOption, path string) error {
+		c, err := NewHttpClient(op)
+		if err != nil {
+			return err
+		}
+
+		res, err := c.Get(ts.URL + path)
+		if err != nil {
+			return err
+		}
+		return res.Body.Close()
+	}
+
+	t.Run("verify", func(t *testing.T) {
+		require.Error(t, get(TransportOption{}, "/"))
+		require.NoError(t, get(TransportOption{InsecureSkipVerify: true}, "/"))
+	})
+
+	t.Run("ca", func(t *testing.T) {
+		caFile := filepath.Join(t.TempDir(), "ca.pem")
+		block := &pem.Block{Type: "CERTIFICATE", Bytes: ts.Certificate().Raw}
+		require.NoError(t, os.WriteFile(caFile, pem.EncodeToMemory(block), 0644))
+		require.NoError(t, get(TransportOption{CACertFile: caFile}, "/"))
+
+		_, err := NewHttpClient(TransportOption{CACertFile: filepath.Join(t.TempDir(), "not-exist.pem")})
+		require.Error(t, err)
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		err := get(TransportOption{InsecureSkipVerify: true, Timeout: 50 * time.Millisecond}, "/slow")
+		require.ErrorContains(t, err, "Timeout")
+	})
+
+	t.Run("default", func(t *testing.T) {
+		SetDefaultTransportOption(TransportOption{InsecureSkipVerify: true})
+		defer SetDefaultTransportOption(TransportOption{})
+		require.NoError(t, get(TransportOption{}, "/"))
+
+		c, err := NewHttpClient(TransportOption{})
+		require.NoError(t, err)
+		require.Equal(t, DefaultRequestTimeout, c.Timeout)
+	})
+
+	t.Run("proxy", func(t *testing.T) {
+		_, err := NewHttpClient(TransportOption{Proxy: "ftp://127.0.0.1"})
+		require.EqualError(t, err, `unsupported proxy scheme "ftp"`)
+
+		_, err = NewHttpClient(TransportOption{Proxy: "socks5://127.0.0.1:1080"})
+		require.NoError(t, err)
+	})
+}
+
+func TestConfigManager_TransportDefaults(t *testing.T) {
+	clearAllConfig()
+	m := NewConfigManager(_CONFIG_PATH)
+	r := require.New(t)
+
+	op, err := m.LoadTransportDefaults()
+	r.NoError(err)
+	r.Equal(TransportOption{}, op)
+
+	want := TransportOption{Timeout: 10 * time.Second, Proxy: "http://proxy"}
+	r.NoError(m.SaveTransportDefaults(want))
+
+	op, err = m.LoadTransportDefaults()
+	r.NoError(err)
+	r.Equal(want, op)
+}
@@ -0,0 +1,25 @@
+package client
+
+import (
+	"fyne.io/fyne/v2/data/binding"
+	"fyne.io/fyne/v2/widget"
+)
+
+// 创建一个与 binding.String 双向同步的 [widget.Select] ，供各 [Client] 的实现使用。
+// 监听器注册在 data 上且不会被移除，应只在创建界面时调用，见 [Client.Box] 。
+func NewBoundSelect(options []string, data binding.String) *widget.Select {
+	sel := widget.NewSelect(options, func(v string) {
+		if cur, _ := data.Get(); cur != v {
+			data.Set(v)
+		}
+	})
+
+	data.AddListener(binding.NewDataListener(func() {
+		v, _ := data.Get()
+		if sel.Selected != v {
+			sel.SetSelected(v)
+		}
+	}))
+
+	return sel
+}